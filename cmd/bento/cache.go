@@ -0,0 +1,111 @@
+// Package main implements the cache command for managing cached node outputs.
+//
+// Nodes that set "cache": true store their outputs in ~/.bento/cache/,
+// keyed by a hash of the node type, resolved parameters and input files.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/hangiri"
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached node outputs",
+	Long: `Manage cached node outputs stored in ~/.bento/cache/
+
+Nodes opt in to caching with "cache": true. When a node's type,
+resolved parameters and input files are unchanged, the cached
+output is reused and the node is skipped.
+
+Commands:
+  ls    - List cached outputs
+  clear - Remove all cached outputs`,
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List cached node outputs",
+	Long: `List cached node outputs, newest first.
+
+Example:
+  bento cache ls`,
+	Args: cobra.NoArgs,
+	RunE: runCacheLs,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached node outputs",
+	Long: `Remove all cached node outputs.
+
+The next run re-executes every cached node.
+
+Example:
+  bento cache clear`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClear,
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheLsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// runCacheLs lists cache entries.
+func runCacheLs(cmd *cobra.Command, args []string) error {
+	entries, err := hangiri.NewDefaultStorage().Cache().List()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to list cache: %v", err))
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Cache is empty")
+		return nil
+	}
+
+	printInfo("Cached Outputs (from ~/.bento/cache/):\n")
+	for _, entry := range entries {
+		fmt.Printf("  %s  %s (%s)\n", entry.Key[:12], entry.NodeID, entry.NodeType)
+		fmt.Printf("    %s ago, %s\n\n", formatDuration(time.Since(entry.CreatedAt)), formatBytes(entry.Size))
+	}
+	fmt.Printf("%d cached outputs\n", len(entries))
+	return nil
+}
+
+// runCacheClear removes all cache entries.
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	removed, err := hangiri.NewDefaultStorage().Cache().Clear()
+	if err != nil {
+		printError(fmt.Sprintf("Failed to clear cache: %v", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Cleared %d cached outputs", removed))
+	return nil
+}
+
+// attachCache enables the output cache for a run unless --no-cache is set.
+func attachCache(chef *itamae.Itamae) {
+	if noCacheFlag {
+		return
+	}
+	chef.SetCache(hangiri.NewDefaultStorage().Cache())
+}
+
+// formatBytes formats a byte count for display.
+func formatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
@@ -0,0 +1,138 @@
+// Package main implements the history command for viewing past runs.
+//
+// Every `bento run` records its variables, status and per-node durations
+// in ~/.bento/history/{run-id}.json.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/logs"
+	"github.com/Develonaut/bento/pkg/miso"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [bento]",
+	Short: "View past bento runs",
+	Long: `View past bento runs recorded in ~/.bento/history/
+
+Lists runs newest first, optionally only those of one bento
+(by name, file name or path).
+
+Examples:
+  bento history                       List all runs
+  bento history product-renders       List runs of one bento
+  bento history show 20250101-150405-a1b2c3   Per-node breakdown of a run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <run-id>",
+	Short: "Show the per-node breakdown of a run",
+	Long: `Show a recorded run: its variables, status and the
+duration and outcome of every node.
+
+Example:
+  bento history show 20250101-150405-a1b2c3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryShow,
+}
+
+func init() {
+	historyCmd.AddCommand(historyShowCmd)
+}
+
+// runHistory lists recorded runs.
+func runHistory(cmd *cobra.Command, args []string) error {
+	bento := ""
+	if len(args) > 0 {
+		bento = args[0]
+	}
+
+	runs, err := logs.ListRuns(miso.LoadBentoHome(), bento)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read history: %v", err))
+		return err
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded yet")
+		return nil
+	}
+
+	printInfo("Run History (from ~/.bento/history/):\n")
+	for _, run := range runs {
+		fmt.Printf("  %s  %s  %s\n", run.ID, formatRunStatus(run.Status), run.Bento)
+		fmt.Printf("    %s, took %s\n\n", run.StartedAt.Format("2006-01-02 15:04:05"), formatDuration(run.Duration()))
+	}
+	fmt.Printf("%d runs\n", len(runs))
+	return nil
+}
+
+// runHistoryShow prints one run with its per-node breakdown.
+func runHistoryShow(cmd *cobra.Command, args []string) error {
+	run, err := logs.LoadRun(miso.LoadBentoHome(), args[0])
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	printInfo(fmt.Sprintf("Run %s\n", run.ID))
+	fmt.Printf("  Bento:    %s\n", run.Bento)
+	if run.BentoPath != "" {
+		fmt.Printf("  Path:     %s\n", run.BentoPath)
+	}
+	fmt.Printf("  Status:   %s\n", formatRunStatus(run.Status))
+	if run.ResumedFrom != "" {
+		fmt.Printf("  Resumed:  from %s\n", run.ResumedFrom)
+	}
+	fmt.Printf("  Started:  %s\n", run.StartedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Duration: %s\n", formatDuration(run.Duration()))
+	if run.Error != "" {
+		fmt.Printf("  Error:    %s\n", run.Error)
+	}
+
+	if len(run.Variables) > 0 {
+		fmt.Println("\nVariables:")
+		for _, name := range sortedKeys(run.Variables) {
+			fmt.Printf("  %s = %v\n", name, run.Variables[name])
+		}
+	}
+
+	fmt.Println("\nNodes:")
+	for _, node := range run.Nodes {
+		fmt.Printf("  %-10s %-8s %s (%s)\n", node.Status,
+			formatDuration(time.Duration(node.DurationMs)*time.Millisecond), node.Name, node.Type)
+		if node.Error != "" {
+			fmt.Printf("             %s\n", node.Error)
+		}
+	}
+	return nil
+}
+
+// formatRunStatus colors a run status for display.
+func formatRunStatus(status string) string {
+	theme := miso.NewManager().GetTheme()
+	switch status {
+	case "success":
+		return theme.Success.Render(status)
+	case "failed":
+		return theme.Error.Render(status)
+	default:
+		return theme.Warning.Render(status)
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
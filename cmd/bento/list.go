@@ -10,8 +10,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Develonaut/bento/pkg/hangiri"
+	"github.com/Develonaut/bento/pkg/logs"
+	"github.com/Develonaut/bento/pkg/miso"
 	"github.com/Develonaut/bento/pkg/neta"
 	"github.com/spf13/cobra"
 )
@@ -70,6 +73,8 @@ func runListFromStorage() error {
 		return nil
 	}
 
+	lastRuns := loadLastRuns()
+
 	printInfo("Available Bentos (from ~/.bento/bentos/):\n")
 	for _, name := range names {
 		// Try to load each bento to get its full name and node count
@@ -85,6 +90,7 @@ func runListFromStorage() error {
 			fmt.Printf("    %s\n", def.Name)
 			fmt.Printf("    %d nodes\n", len(def.Nodes))
 		}
+		printLastRun(lastRuns, bentoFilePath(filepath.Join(miso.LoadBentoHome(), "bentos", name+".bento.json")))
 		fmt.Println()
 	}
 	fmt.Printf("\n%d bentos found\n", len(names))
@@ -110,9 +116,11 @@ func displayBentos(bentos []bentoInfo) {
 		return
 	}
 
+	lastRuns := loadLastRuns()
+
 	printInfo("Available Bentos:\n")
 	for _, bento := range bentos {
-		printBento(bento)
+		printBento(bento, lastRuns)
 	}
 	fmt.Printf("\n%d bentos found\n", len(bentos))
 }
@@ -181,11 +189,29 @@ func countNodes(def *neta.Definition) int {
 }
 
 // printBento prints a single bento entry.
-func printBento(b bentoInfo) {
+func printBento(b bentoInfo, lastRuns map[string]logs.RunRecord) {
 	fmt.Printf("  %s\n", b.FileName)
 	if b.Name != "" {
 		fmt.Printf("    %s\n", b.Name)
 		fmt.Printf("    %d nodes\n", b.NumNodes)
 	}
+	printLastRun(lastRuns, bentoFilePath(b.Path))
 	fmt.Println()
 }
+
+// loadLastRuns loads the most recent run of each bento.
+// History is optional, so read errors just hide the last-run status.
+func loadLastRuns() map[string]logs.RunRecord {
+	lastRuns, err := logs.LastRuns(miso.LoadBentoHome())
+	if err != nil {
+		return nil
+	}
+	return lastRuns
+}
+
+// printLastRun prints the last-run status of a bento, if it has run before.
+func printLastRun(lastRuns map[string]logs.RunRecord, path string) {
+	if run, ok := lastRuns[path]; ok {
+		fmt.Printf("    last run: %s\n", logs.FormatLastRun(run, time.Now()))
+	}
+}
@@ -48,7 +48,9 @@ Available Commands:
   • new      - Create a new bento workflow template
   • docs     - View documentation
   • secrets  - Manage secrets securely
+  • cache    - Manage cached node outputs
   • logs     - View and tail execution logs
+  • history  - View past runs
   • version  - Show version information`,
 }
 
@@ -74,7 +76,7 @@ func main() {
 
 // isKnownSubcommand checks if the arg is a registered subcommand.
 func isKnownSubcommand(arg string) bool {
-	knownCommands := []string{"run", "validate", "list", "new", "docs", "secrets", "logs", "version", "v", "help", "config", "tui"}
+	knownCommands := []string{"run", "validate", "list", "new", "docs", "secrets", "cache", "logs", "history", "version", "v", "help", "config", "tui"}
 	for _, cmd := range knownCommands {
 		if arg == cmd {
 			return true
@@ -107,6 +109,16 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&verboseFlag, "verbose", false, "Verbose output")
 	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 10*time.Minute, "Execution timeout")
 	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would be executed without running")
+	rootCmd.PersistentFlags().StringVar(&resumeFlag, "resume", "", "Resume a failed run by ID, skipping completed nodes")
+	rootCmd.PersistentFlags().StringArrayVar(&setFlags, "set", nil, "Set a bento variable (KEY=VALUE, repeatable)")
+	rootCmd.PersistentFlags().StringVar(&varsFileFlag, "vars-file", "", "Load bento variables from a JSON file")
+	rootCmd.PersistentFlags().StringVar(&eventsFlag, "events", "", "Write machine-readable progress events (ndjson)")
+	rootCmd.PersistentFlags().StringVar(&eventsFileFlag, "events-file", "", "Write the event stream to a file instead of stdout")
+	rootCmd.PersistentFlags().StringVar(&traceFlag, "trace", "", "Write an execution trace (spans per node, iteration and retry) to a file")
+	rootCmd.PersistentFlags().StringVar(&traceFormatFlag, "trace-format", "chrome", "Trace file format: chrome (Perfetto) or otlp (OTLP JSON)")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "Ignore cached node outputs and re-execute every node")
+	rootCmd.PersistentFlags().BoolVar(&lenientFlag, "lenient-templates", false, "Leave unresolvable templates as they are instead of failing the node")
+	rootCmd.PersistentFlags().DurationVar(&gracePeriodFlag, "grace-period", 10*time.Second, "Time in-flight nodes get to finish after Ctrl+C before they are cancelled")
 
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(validateCmd)
@@ -114,7 +126,9 @@ func init() {
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(docsCmd)
 	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(cacheCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(tuiCmd)
@@ -8,7 +8,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
 	"strings"
 	"time"
 
@@ -38,10 +40,19 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh %dm", hours, mins)
 }
 
+// messageOutput returns where messages are printed: stdout, or stderr when
+// stdout carries the event stream.
+func messageOutput() io.Writer {
+	if eventsToStdout() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 // printSuccess prints a success message with random success emoji.
 func printSuccess(message string) {
 	emoji := successEmojis[rand.Intn(len(successEmojis))]
-	fmt.Printf("\n%s %s\n", emoji, message)
+	fmt.Fprintf(messageOutput(), "\n%s %s\n", emoji, message)
 }
 
 // printError prints an error message with random error emoji and color-coded text.
@@ -49,7 +60,7 @@ func printError(message string) {
 	emoji := errorEmojis[rand.Intn(len(errorEmojis))]
 	manager := miso.NewManager()
 	theme := manager.GetTheme()
-	fmt.Printf("\n%s %s\n", emoji, theme.Error.Render(message))
+	fmt.Fprintf(messageOutput(), "\n%s %s\n", emoji, theme.Error.Render(message))
 }
 
 // Approved sushi emojis for info messages (from .claude/EMOJIS.md)
@@ -99,17 +110,17 @@ var errorStatusWords = []string{
 func printInfo(message string) {
 	// Use bento box emoji 🍱 for branding on "Running bento:" messages
 	if strings.HasPrefix(message, "Running bento:") {
-		fmt.Printf("🍱 %s\n", message)
+		fmt.Fprintf(messageOutput(), "🍱 %s\n", message)
 		return
 	}
 
 	emoji := sushiEmojis[rand.Intn(len(sushiEmojis))]
-	fmt.Printf("%s %s\n", emoji, message)
+	fmt.Fprintf(messageOutput(), "%s %s\n", emoji, message)
 }
 
 // printCheck prints a check mark for completed items.
 func printCheck(message string) {
-	fmt.Printf("✓ %s\n", message)
+	fmt.Fprintf(messageOutput(), "✓ %s\n", message)
 }
 
 // getErrorStatusWord returns a random error status word.
@@ -10,17 +10,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/mattn/go-isatty"
 
 	"github.com/Develonaut/bento/pkg/hangiri"
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/miso"
 	"github.com/Develonaut/bento/pkg/neta"
 	"github.com/Develonaut/bento/pkg/omakase"
 	"github.com/Develonaut/bento/pkg/pantry"
 	"github.com/spf13/cobra"
 
+	branch "github.com/Develonaut/bento/pkg/neta/library/branch"
 	editfields "github.com/Develonaut/bento/pkg/neta/library/editfields"
 	filesystem "github.com/Develonaut/bento/pkg/neta/library/filesystem"
 	group "github.com/Develonaut/bento/pkg/neta/library/group"
@@ -34,9 +38,19 @@ import (
 )
 
 var (
-	verboseFlag bool
-	timeoutFlag time.Duration
-	dryRunFlag  bool
+	verboseFlag     bool
+	timeoutFlag     time.Duration
+	dryRunFlag      bool
+	resumeFlag      string
+	noCacheFlag     bool
+	setFlags        []string
+	varsFileFlag    string
+	eventsFlag      string
+	eventsFileFlag  string
+	traceFlag       string
+	traceFormatFlag string
+	lenientFlag     bool
+	gracePeriodFlag time.Duration
 )
 
 var runCmd = &cobra.Command{
@@ -50,7 +64,16 @@ and reports progress and results.
 Examples:
   bento run workflow.bento.json
   bento run workflow.bento.json --verbose
-  bento run workflow.bento.json --timeout 30m`,
+  bento run workflow.bento.json --timeout 30m
+  bento run workflow.bento.json --resume 20250101-150405-a1b2c3
+  bento run workflow.bento.json --set PRODUCT_PATH=./chair --set ZOOM_MULTIPLIER=1.5
+  bento run workflow.bento.json --vars-file vars.json
+  bento run workflow.bento.json --events ndjson
+  bento run workflow.bento.json --events ndjson --events-file events.ndjson
+  bento run workflow.bento.json --trace trace.json
+  bento run workflow.bento.json --trace trace.json --trace-format otlp
+  bento run workflow.bento.json --lenient-templates
+  bento run workflow.bento.json --grace-period 30s`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRun,
 }
@@ -64,21 +87,36 @@ func init() {
 
 // runRun executes the run command logic.
 func runRun(cmd *cobra.Command, args []string) error {
+	if err := validateEventsFlag(); err != nil {
+		printError(err.Error())
+		return err
+	}
+	if err := validateTraceFlags(); err != nil {
+		printError(err.Error())
+		return err
+	}
+
 	def, err := loadAndValidate(args[0])
 	if err != nil {
 		return err
 	}
 
+	vars, err := resolveRunVariables(def)
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+
 	// If dry run, show what would be executed and exit
 	if dryRunFlag {
-		return showDryRun(def)
+		return showDryRun(def, vars)
 	}
 
-	// Detect TTY mode
-	if isTTY() {
-		return executeTUI(def)
+	// Detect TTY mode (event streams always use simple mode)
+	if isTTY() && !eventsEnabled() {
+		return executeTUI(def, args[0], vars)
 	}
-	return executeSimple(def)
+	return executeSimple(def, args[0], vars)
 }
 
 // loadAndValidate loads and validates a bento.
@@ -136,6 +174,41 @@ func loadBento(path string) (*neta.Definition, error) {
 	return loadBentoFromStorage(path)
 }
 
+// attachSubBentos lets "bento" neta load stored bentos by name and resolve
+// relative paths against the directory of the bento being run. Sub-bentos
+// are validated before they run.
+func attachSubBentos(chef *itamae.Itamae, bentoPath string) {
+	chef.SetBentoLoader(hangiri.NewDefaultStorage())
+	chef.SetBentoValidator(omakase.New())
+	chef.SetBentoDir(bentoDirectory(bentoPath))
+}
+
+// bentoDirectory returns the directory of a bento given as in loadBento.
+// Bentos loaded from storage resolve against the storage directory.
+func bentoDirectory(path string) string {
+	if isValidFilePath(path) || isValidFilePath(path+".bento.json") {
+		return filepath.Dir(path)
+	}
+	return filepath.Join(miso.LoadBentoHome(), "bentos")
+}
+
+// bentoFilePath returns the absolute path of a bento given as in loadBento.
+// Used to match run history with bento lists.
+func bentoFilePath(path string) string {
+	switch {
+	case isValidFilePath(path):
+	case isValidFilePath(path + ".bento.json"):
+		path += ".bento.json"
+	default:
+		name := strings.TrimSuffix(path, ".bento.json")
+		path = filepath.Join(miso.LoadBentoHome(), "bentos", name+".bento.json")
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
 // isValidFilePath checks if the path exists as a file.
 func isValidFilePath(path string) bool {
 	info, err := os.Stat(path)
@@ -185,11 +258,13 @@ func createPantry() *pantry.Pantry {
 	p.RegisterFactory("file-system", func() neta.Executable { return filesystem.New() })
 	p.RegisterFactory("group", func() neta.Executable { return group.New() })
 	p.RegisterFactory("http-request", func() neta.Executable { return httpneta.New() })
+	p.RegisterFactory("if", func() neta.Executable { return branch.NewIf() })
 	p.RegisterFactory("image", func() neta.Executable { return image.New() })
 	p.RegisterFactory("loop", func() neta.Executable { return loop.New() })
 	p.RegisterFactory("parallel", func() neta.Executable { return parallel.New() })
 	p.RegisterFactory("shell-command", func() neta.Executable { return shellcommand.New() })
 	p.RegisterFactory("spreadsheet", func() neta.Executable { return spreadsheet.New() })
+	p.RegisterFactory("switch", func() neta.Executable { return branch.NewSwitch() })
 	p.RegisterFactory("transform", func() neta.Executable { return transform.New() })
 
 	return p
@@ -203,10 +278,11 @@ func validateBento(def *neta.Definition) error {
 }
 
 // showDryRun displays what would be executed without running.
-func showDryRun(def *neta.Definition) error {
+func showDryRun(def *neta.Definition, vars map[string]interface{}) error {
 	printInfo("DRY RUN MODE - No execution will occur")
 	fmt.Printf("\nWould execute bento: %s\n", def.Name)
 	fmt.Printf("Total nodes to execute: %d\n\n", len(def.Nodes))
+	printVariables(def, vars)
 
 	if verboseFlag {
 		printInfo("Nodes that would be executed:")
@@ -220,7 +296,7 @@ func showDryRun(def *neta.Definition) error {
 	fmt.Println("Running preflight checks...")
 	validator := createValidator()
 	ctx := context.Background()
-	if err := validator.PreflightCheck(ctx, def); err != nil {
+	if err := validator.PreflightCheck(ctx, def, vars); err != nil {
 		printError(fmt.Sprintf("Preflight check failed: %v", err))
 		fmt.Println("\n❌ Dry run failed - fix the issues above before running")
 		return err
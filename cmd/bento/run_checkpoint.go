@@ -0,0 +1,70 @@
+// Package main implements checkpointing for the run command.
+//
+// Every run writes completed node outputs to {bento-home}/runs/{run-id}/
+// so a failed run can be picked up again with --resume {run-id}.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/logs"
+	"github.com/Develonaut/bento/pkg/miso"
+)
+
+// runsDirectory returns the directory that holds per-run checkpoints.
+func runsDirectory() string {
+	return filepath.Join(miso.LoadBentoHome(), "runs")
+}
+
+// prepareCheckpoint creates a checkpoint for a new run, or loads the
+// checkpoint of an earlier run when --resume is set.
+// Returns the run ID and the checkpoint.
+func prepareCheckpoint() (string, *itamae.Checkpoint, error) {
+	if resumeFlag != "" {
+		cp, err := itamae.LoadCheckpoint(filepath.Join(runsDirectory(), resumeFlag))
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot resume run '%s': %w", resumeFlag, err)
+		}
+		printInfo(fmt.Sprintf("Resuming run %s (%d completed nodes)", resumeFlag, cp.NodeCount()))
+		return resumeFlag, cp, nil
+	}
+
+	runID := logs.NewRunID()
+	cp, err := itamae.NewCheckpoint(filepath.Join(runsDirectory(), runID))
+	if err != nil {
+		return "", nil, err
+	}
+	return runID, cp, nil
+}
+
+// attachCheckpoint enables checkpointing on the chef.
+// Checkpoint failures are reported but never block execution.
+func attachCheckpoint(chef *itamae.Itamae) (string, *itamae.Checkpoint) {
+	runID, cp, err := prepareCheckpoint()
+	if err != nil {
+		if resumeFlag != "" {
+			printError(err.Error())
+			os.Exit(1)
+		}
+		printError(fmt.Sprintf("Warning: Checkpointing disabled: %v", err))
+		return "", nil
+	}
+	chef.SetCheckpoint(cp)
+	return runID, cp
+}
+
+// finishCheckpoint removes the checkpoint after a successful run, or prints
+// the resume hint after a failure.
+func finishCheckpoint(runID string, cp *itamae.Checkpoint, runErr error) {
+	if cp == nil {
+		return
+	}
+	if runErr == nil {
+		_ = os.RemoveAll(cp.Dir())
+		return
+	}
+	printInfo(fmt.Sprintf("Completed work was saved. Resume with: --resume %s", runID))
+}
@@ -0,0 +1,67 @@
+// Package main implements the machine-readable event stream for the run command.
+//
+// With --events ndjson every progress update is written as one JSON object
+// per line, to stdout or to the file given with --events-file.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/miso"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// eventsFormatNDJSON is the only supported --events format.
+const eventsFormatNDJSON = "ndjson"
+
+// eventsEnabled reports whether an event stream was requested.
+func eventsEnabled() bool {
+	return eventsFlag != "" || eventsFileFlag != ""
+}
+
+// eventsToStdout reports whether events replace the human-readable output.
+func eventsToStdout() bool {
+	return eventsEnabled() && eventsFileFlag == ""
+}
+
+// validateEventsFlag checks the --events format.
+func validateEventsFlag() error {
+	if eventsFlag != "" && eventsFlag != eventsFormatNDJSON {
+		return fmt.Errorf("unsupported --events format '%s' (use %s)", eventsFlag, eventsFormatNDJSON)
+	}
+	return nil
+}
+
+// openEventStream creates the event messenger when --events is set.
+// Returns a nil messenger when events are disabled. The returned close
+// function is always safe to call.
+func openEventStream(def *neta.Definition) (*miso.EventMessenger, func(), error) {
+	if !eventsEnabled() {
+		return nil, func() {}, nil
+	}
+	if eventsFileFlag == "" {
+		return miso.NewEventMessenger(os.Stdout, def), func() {}, nil
+	}
+
+	f, err := os.Create(eventsFileFlag)
+	if err != nil {
+		return nil, func() {}, fmt.Errorf("failed to create events file: %w", err)
+	}
+	return miso.NewEventMessenger(f, def), func() { _ = f.Close() }, nil
+}
+
+// displayMessengers returns the messengers that show progress in simple mode:
+// the human-readable output (unless stdout carries the event stream) and the
+// event stream.
+func displayMessengers(human itamae.ProgressMessenger, events *miso.EventMessenger) []itamae.ProgressMessenger {
+	var messengers []itamae.ProgressMessenger
+	if !eventsToStdout() {
+		messengers = append(messengers, human)
+	}
+	if events != nil {
+		messengers = append(messengers, events)
+	}
+	return messengers
+}
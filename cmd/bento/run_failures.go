@@ -0,0 +1,40 @@
+// Package main implements the loop failure summary for the run command.
+//
+// Loops that continue on error finish successfully even when some
+// iterations failed. The summary lists those items so they can be rerun.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+)
+
+// maxItemWidth is the longest item shown in the failure summary.
+const maxItemWidth = 60
+
+// printLoopFailures lists the failed iterations of loops that continued on error.
+func printLoopFailures(result *itamae.Result) {
+	if result == nil || len(result.LoopFailures) == 0 {
+		return
+	}
+
+	fmt.Printf("\n⚠️  %d loop iteration(s) failed:\n", len(result.LoopFailures))
+	for _, failure := range result.LoopFailures {
+		fmt.Printf("  %s[%d] %s at %s: %s\n",
+			failure.LoopID, failure.Index, formatItem(failure.Item), failure.NodeID, failure.Error)
+	}
+}
+
+// formatItem renders a loop item as compact JSON, shortened to maxItemWidth.
+func formatItem(item interface{}) string {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Sprint(item)
+	}
+	if s := []rune(string(data)); len(s) > maxItemWidth {
+		return string(s[:maxItemWidth-3]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,36 @@
+// Package main implements run history recording for the run command.
+//
+// Every run is recorded in {bento-home}/history/{run-id}.json with its
+// variables, status and per-node durations (see `bento history`).
+package main
+
+import (
+	"fmt"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/logs"
+	"github.com/Develonaut/bento/pkg/miso"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// startHistory starts recording a run. The recorder is passed to the chef
+// as (one of) its messengers.
+func startHistory(def *neta.Definition, bentoPath string, vars map[string]interface{}) *logs.Recorder {
+	return logs.NewRecorder(def.Name, bentoFilePath(bentoPath), vars)
+}
+
+// finishHistory saves the run record under the run's checkpoint ID. A
+// resumed run gets an ID of its own, so the record of the run it resumed
+// is kept, and names that run in ResumedFrom.
+// History failures are reported but never change the run's outcome.
+func finishHistory(recorder *logs.Recorder, runID string, result *itamae.Result, runErr error) {
+	if runID == "" || resumeFlag != "" {
+		runID = logs.NewRunID()
+	}
+
+	record := recorder.Finish(runID, string(result.Status), runErr)
+	record.ResumedFrom = resumeFlag
+	if err := logs.SaveRun(miso.LoadBentoHome(), record); err != nil {
+		printError(fmt.Sprintf("Warning: Failed to save run history: %v", err))
+	}
+}
@@ -0,0 +1,77 @@
+// Package main implements signal handling for the run command.
+//
+// The first Ctrl+C (SIGINT) or SIGTERM cancels the run: no new node starts,
+// in-flight nodes get --grace-period to finish, then the bento's onCancel
+// and finally nodes run. Shell commands run in their own process groups, so
+// the signal doesn't reach them directly: once the grace period is over
+// their groups get SIGTERM, then SIGKILL. A second signal kills them and
+// exits immediately.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta/library/shellcommand"
+)
+
+// exitCodeCancelled is the exit code of a run stopped by a signal (128 + SIGINT).
+const exitCodeCancelled = 130
+
+// runContext returns the context a run executes with. It is cancelled by
+// the first SIGINT or SIGTERM, or when --timeout elapses. The returned
+// function stops listening for signals.
+func runContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			fmt.Fprintf(os.Stderr, "\n🛑 Received %s, cancelling bento (grace period %s, press Ctrl+C again to force exit)\n",
+				sig, gracePeriodFlag)
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-signals:
+			fmt.Fprintln(os.Stderr, "\n🛑 Forced exit")
+			shellcommand.KillRunning()
+			os.Exit(exitCodeCancelled)
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
+
+// exitCode returns the process exit code of a failed or cancelled run.
+func exitCode(result *itamae.Result) int {
+	if result != nil && result.Status == itamae.StatusCancelled {
+		return exitCodeCancelled
+	}
+	return 1
+}
+
+// printRunError reports a run that failed or was cancelled.
+func printRunError(result *itamae.Result, err error) {
+	if result != nil && result.Status == itamae.StatusCancelled {
+		printError(fmt.Sprintf("Bento cancelled after %s: %v", formatDuration(result.Duration), err))
+		return
+	}
+	// Get random error status word
+	statusWord := getErrorStatusWord()
+	printError(fmt.Sprintf("Oh no! Bento is %s: %v", statusWord, err))
+}
@@ -5,7 +5,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -16,14 +15,26 @@ import (
 )
 
 // executeSimple executes bento with simple single-line progress (non-TTY mode).
-func executeSimple(def *neta.Definition) error {
+func executeSimple(def *neta.Definition, bentoPath string, vars map[string]interface{}) error {
 	// Get theme and palette from miso manager
 	manager := miso.NewManager()
 	theme := manager.GetTheme()
 	palette := manager.GetPalette()
 
 	// Create simple messenger that prints to stdout
-	messenger := miso.NewSimpleMessenger(theme, palette)
+	human := miso.NewSimpleMessenger(theme, palette)
+
+	// Add the machine-readable event stream (--events)
+	events, closeEvents, err := openEventStream(def)
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+	defer closeEvents()
+
+	// Record the run for `bento history`
+	recorder := startHistory(def, bentoPath, vars)
+	messenger := miso.NewMultiMessenger(append(displayMessengers(human, events), recorder)...)
 
 	// Create pantry and file logger (always log to file)
 	p := createPantry()
@@ -36,30 +47,56 @@ func executeSimple(def *neta.Definition) error {
 		defer logFile.Close()
 	}
 
-	// Also log to stdout if verbose
-	if verboseFlag && logger != nil {
+	// Also log to stdout if verbose (unless stdout carries the event stream)
+	if verboseFlag && logger != nil && !eventsToStdout() {
 		logger = createDualLogger(logger)
 	}
 
 	// Create chef with messenger
 	chef := itamae.NewWithMessenger(p, logger, messenger)
+	runID, cp := attachCheckpoint(chef)
+	attachCache(chef)
+	attachSubBentos(chef, bentoPath)
+	attachVariables(chef, vars)
+	chef.SetStrictTemplates(!lenientFlag)
+	chef.SetGracePeriod(gracePeriodFlag)
+	trace := attachTrace(chef)
 
 	// Execute bento
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag)
-	defer cancel()
+	ctx, stop := runContext()
+	defer stop()
 
+	if events != nil {
+		events.SendRunStarted(def)
+	}
 	start := time.Now()
 	result, err := chef.Serve(ctx, def)
 	duration := time.Since(start)
+	finishCheckpoint(runID, cp, err)
+	finishHistory(recorder, runID, result, err)
+	writeTrace(trace, def)
+	if events != nil {
+		events.SendRunFinished(def, result)
+	}
 
 	if err != nil {
-		// Get random error status word
-		statusWord := getErrorStatusWord()
-		printError(fmt.Sprintf("Oh no! Bento is %s: %v", statusWord, err))
-		os.Exit(1)
+		if !eventsToStdout() {
+			printRunError(result, err)
+		}
+		closeEvents()
+		os.Exit(exitCode(result))
+	}
+
+	if eventsToStdout() {
+		return nil
 	}
 
-	printSuccess(fmt.Sprintf("Delicious! Bento executed %d nodes successfully in %s",
-		result.NodesExecuted, formatDuration(duration)))
+	summary := fmt.Sprintf("Delicious! Bento executed %d nodes successfully in %s",
+		result.NodesExecuted, formatDuration(duration))
+	if result.NodesCached > 0 {
+		summary += fmt.Sprintf(" (%d from cache)", result.NodesCached)
+	}
+	printSuccess(summary)
+	printLoopFailures(result)
 	return nil
 }
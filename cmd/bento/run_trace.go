@@ -0,0 +1,69 @@
+// Package main implements execution trace export for the run command.
+//
+// With --trace every node, group, loop iteration and retry attempt is
+// recorded as a span and written to a file when the run ends.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Supported --trace-format values.
+const (
+	traceFormatChrome = "chrome"
+	traceFormatOTLP   = "otlp"
+)
+
+// validateTraceFlags checks the --trace-format value.
+func validateTraceFlags() error {
+	if traceFormatFlag != traceFormatChrome && traceFormatFlag != traceFormatOTLP {
+		return fmt.Errorf("unsupported --trace-format '%s' (use %s or %s)",
+			traceFormatFlag, traceFormatChrome, traceFormatOTLP)
+	}
+	return nil
+}
+
+// attachTrace enables span recording when --trace is set.
+// Returns nil when tracing is disabled.
+func attachTrace(chef *itamae.Itamae) *itamae.TraceRecorder {
+	if traceFlag == "" {
+		return nil
+	}
+	recorder := itamae.NewTraceRecorder()
+	chef.SetTracer(recorder)
+	return recorder
+}
+
+// writeTrace writes the recorded spans to the --trace file.
+// Failures are reported but never change the run's outcome.
+func writeTrace(recorder *itamae.TraceRecorder, def *neta.Definition) {
+	if recorder == nil {
+		return
+	}
+
+	if err := writeTraceFile(recorder, def); err != nil {
+		printError(fmt.Sprintf("Warning: Failed to write trace: %v", err))
+	}
+}
+
+// writeTraceFile writes the trace in the selected format.
+func writeTraceFile(recorder *itamae.TraceRecorder, def *neta.Definition) error {
+	f, err := os.Create(traceFlag)
+	if err != nil {
+		return err
+	}
+
+	if traceFormatFlag == traceFormatOTLP {
+		err = recorder.WriteOTLP(f, def.Name)
+	} else {
+		err = recorder.WriteChromeTrace(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
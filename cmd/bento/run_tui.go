@@ -5,7 +5,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"time"
@@ -16,7 +15,7 @@ import (
 )
 
 // executeTUI executes bento with detailed log output to stdout.
-func executeTUI(def *neta.Definition) error {
+func executeTUI(def *neta.Definition, bentoPath string, vars map[string]interface{}) error {
 	// Create pantry and file logger
 	p := createPantry()
 	logger, logFile, err := createFileLogger()
@@ -33,26 +32,36 @@ func executeTUI(def *neta.Definition) error {
 		logger = createDualLogger(logger)
 	}
 
-	// Create chef with logger (no messenger for TUI mode)
-	chef := itamae.New(p, logger)
+	// Create chef with logger (the only messenger records run history)
+	recorder := startHistory(def, bentoPath, vars)
+	chef := itamae.NewWithMessenger(p, logger, recorder)
 
 	// Load slowMo delay from config for animations
 	slowMoMs := miso.LoadSlowMoDelay()
 	chef.SetSlowMoDelay(time.Duration(slowMoMs) * time.Millisecond)
+	runID, cp := attachCheckpoint(chef)
+	attachCache(chef)
+	attachSubBentos(chef, bentoPath)
+	attachVariables(chef, vars)
+	chef.SetStrictTemplates(!lenientFlag)
+	chef.SetGracePeriod(gracePeriodFlag)
+	trace := attachTrace(chef)
 
 	// Execute bento
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag)
-	defer cancel()
+	ctx, stop := runContext()
+	defer stop()
 
 	result, err := chef.Serve(ctx, def)
+	finishCheckpoint(runID, cp, err)
+	finishHistory(recorder, runID, result, err)
+	writeTrace(trace, def)
 
 	if err != nil {
 		// Error message is already logged by itamae
-		os.Exit(1)
+		os.Exit(exitCode(result))
 	}
 
 	// Success message is already logged by itamae
-	// Just exit cleanly
-	_ = result
+	printLoopFailures(result)
 	return nil
 }
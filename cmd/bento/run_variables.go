@@ -0,0 +1,96 @@
+// Package main implements bento variable input for the run command.
+//
+// Declared variables are filled from --set KEY=VALUE flags, a --vars-file
+// JSON object and the environment, then type-checked before the run starts.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/omakase"
+)
+
+// resolveRunVariables collects and type-checks the bento's variable values.
+// --set values take precedence over the vars file.
+func resolveRunVariables(def *neta.Definition) (map[string]interface{}, error) {
+	values, err := loadVarsFile(varsFileFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := parseSetFlags(setFlags)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range sets {
+		values[name] = value
+	}
+
+	return omakase.ResolveVariables(def.Variables, values)
+}
+
+// loadVarsFile reads a JSON object of variable values.
+// Returns an empty map when no file is given.
+func loadVarsFile(path string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if path == "" {
+		return values, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vars file: %w", err)
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("vars file must be a JSON object: %w", err)
+	}
+	return values, nil
+}
+
+// parseSetFlags parses KEY=VALUE pairs from --set flags.
+func parseSetFlags(sets []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(sets))
+	for _, set := range sets {
+		name, value, ok := strings.Cut(set, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set '%s' (expected KEY=VALUE)", set)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// attachVariables passes resolved variable values to the chef.
+func attachVariables(chef *itamae.Itamae, vars map[string]interface{}) {
+	chef.SetVariables(vars)
+}
+
+// printVariables lists the declared variables and their resolved values.
+func printVariables(def *neta.Definition, vars map[string]interface{}) {
+	if len(def.Variables) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(def.Variables))
+	for _, v := range def.Variables {
+		names = append(names, v.Name)
+	}
+	sort.Strings(names)
+
+	printInfo("Variables:")
+	for _, name := range names {
+		value, ok := vars[name]
+		if !ok {
+			fmt.Printf("  %s (unset)\n", name)
+			continue
+		}
+		fmt.Printf("  %s = %v\n", name, value)
+	}
+	fmt.Println()
+}
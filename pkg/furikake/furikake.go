@@ -0,0 +1,94 @@
+// Package furikake provides the built-in functions for bento templates.
+//
+// "Furikake" (ふりかけ - rice seasoning) is sprinkled over rice to add
+// flavor. These functions are sprinkled over templates so values can be
+// shaped in place, without extra edit-fields or transform neta:
+//
+//	"{{.item.SKU | lower}}-{{printf \"%03d\" .index}}.png"
+//	"{{.item.color | default \"red\"}}"
+//	"{{now | date \"2006-01-02\"}}"
+//
+// The same set is used by itamae parameter templates and the edit-fields
+// neta, so both engines behave identically.
+//
+// Functions that take a piped value take it as their last argument.
+package furikake
+
+import (
+	"path/filepath"
+	"text/template"
+)
+
+// Funcs returns the template function set.
+//
+// Strings:
+//   - lower, upper, title, camel, snake, kebab, slug
+//   - trim, trimPrefix, trimSuffix, replace, contains, hasPrefix, hasSuffix, split
+//   - regexMatch, regexFind, regexReplace
+//   - padLeft, padRight
+//
+// Values:
+//   - default, coalesce, toJson, fromJson
+//   - join, first, last
+//
+// Math: add, sub, mul, div, mod, min, max, round, floor, ceil
+//
+// Dates: now, date
+//
+// Paths: pathJoin, dir, ext, basename
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		// Strings
+		"lower":        lower,
+		"upper":        upper,
+		"title":        title,
+		"camel":        camel,
+		"snake":        snake,
+		"kebab":        kebab,
+		"slug":         slug,
+		"trim":         trim,
+		"trimPrefix":   trimPrefix,
+		"trimSuffix":   trimSuffix,
+		"replace":      replace,
+		"contains":     contains,
+		"hasPrefix":    hasPrefix,
+		"hasSuffix":    hasSuffix,
+		"split":        split,
+		"regexMatch":   regexMatch,
+		"regexFind":    regexFind,
+		"regexReplace": regexReplace,
+		"padLeft":      padLeft,
+		"padRight":     padRight,
+
+		// Values
+		"default":  defaultValue,
+		"coalesce": coalesce,
+		"toJson":   toJSON,
+		"fromJson": fromJSON,
+		"join":     join,
+		"first":    first,
+		"last":     last,
+
+		// Math
+		"add":   add,
+		"sub":   sub,
+		"mul":   mul,
+		"div":   div,
+		"mod":   mod,
+		"min":   minimum,
+		"max":   maximum,
+		"round": round,
+		"floor": floor,
+		"ceil":  ceil,
+
+		// Dates
+		"now":  now,
+		"date": date,
+
+		// Paths
+		"pathJoin": filepath.Join,
+		"dir":      filepath.Dir,
+		"ext":      filepath.Ext,
+		"basename": filepath.Base,
+	}
+}
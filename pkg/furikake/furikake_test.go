@@ -0,0 +1,132 @@
+package furikake
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+)
+
+// render executes a template with the function set against data.
+func render(t *testing.T, tmpl string, data interface{}) (string, error) {
+	t.Helper()
+	parsed, err := template.New("test").Funcs(Funcs()).Parse(tmpl)
+	if err != nil {
+		t.Fatalf("parse %q: %v", tmpl, err)
+	}
+	var buf bytes.Buffer
+	err = parsed.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// TestFuncs verifies each function through a template.
+func TestFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "Combat Dog (Supplies)",
+		"key":   "productSKU_id",
+		"index": 7,
+		"price": "19.987",
+		"count": 10.0,
+		"tags":  []interface{}{"red", "blue"},
+		"names": []string{"a", "b", "c"},
+		"raw":   `{"id":42}`,
+		"empty": "",
+		"when":  "2024-03-05T10:00:00Z",
+		"path":  "/renders/chair.png",
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{.name | lower}}`, "combat dog (supplies)"},
+		{`{{.name | upper}}`, "COMBAT DOG (SUPPLIES)"},
+		{`{{"combat dog" | title}}`, "Combat Dog"},
+		{`{{.key | camel}}`, "productSkuId"},
+		{`{{.key | snake}}`, "product_sku_id"},
+		{`{{"Product Name" | kebab}}`, "product-name"},
+		{`{{.name | slug}}`, "combat-dog-supplies"},
+		{`{{"  chair  " | trim}}`, "chair"},
+		{`{{"SKU-001" | trimPrefix "SKU-"}}`, "001"},
+		{`{{"chair.png" | trimSuffix ".png"}}`, "chair"},
+		{`{{"a b c" | replace " " "_"}}`, "a_b_c"},
+		{`{{.name | contains "Dog"}}`, "true"},
+		{`{{.path | hasPrefix "/renders"}}`, "true"},
+		{`{{.path | hasSuffix ".jpg"}}`, "false"},
+		{`{{"a/b/c" | split "/" | last}}`, "c"},
+		{`{{.key | regexMatch "^[a-z]+SKU"}}`, "true"},
+		{`{{"order 1234 shipped" | regexFind "[0-9]+"}}`, "1234"},
+		{`{{"2024-03-05" | regexReplace "(\\d+)-(\\d+)-(\\d+)" "$3/$2/$1"}}`, "05/03/2024"},
+		{`{{.index | padLeft 3 "0"}}`, "007"},
+		{`{{"ab" | padRight 5 "."}}`, "ab..."},
+		{`{{.missing | default "red"}}`, "red"},
+		{`{{.empty | default "red"}}`, "red"},
+		{`{{.name | default "red"}}`, "Combat Dog (Supplies)"},
+		{`{{coalesce .missing .empty "fallback"}}`, "fallback"},
+		{`{{.tags | toJson}}`, `["red","blue"]`},
+		{`{{(.raw | fromJson).id}}`, "42"},
+		{`{{.tags | join ", "}}`, "red, blue"},
+		{`{{.names | join "-"}}`, "a-b-c"},
+		{`{{.tags | first}}`, "red"},
+		{`{{.names | last}}`, "c"},
+		{`{{.index | add 1}}`, "8"},
+		{`{{.index | sub 2}}`, "5"},
+		{`{{.index | mul 3}}`, "21"},
+		{`{{.index | div 2}}`, "3"},
+		{`{{.count | div 4}}`, "2"},
+		{`{{.price | div 2}}`, "9.9935"},
+		{`{{.index | mod 4}}`, "3"},
+		{`{{min 3 .index 5}}`, "3"},
+		{`{{max 3 .index 5}}`, "7"},
+		{`{{.price | round 2}}`, "19.99"},
+		{`{{.price | floor}}`, "19"},
+		{`{{.price | ceil}}`, "20"},
+		{`{{.when | date "2006-01-02"}}`, "2024-03-05"},
+		{`{{pathJoin "renders" "chair.png"}}`, "renders/chair.png"},
+		{`{{.path | dir}}`, "/renders"},
+		{`{{.path | ext}}`, ".png"},
+		{`{{.path | basename}}`, "chair.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			got, err := render(t, tt.tmpl, data)
+			if err != nil {
+				t.Fatalf("execute failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFuncs_Now verifies now can be formatted with date.
+func TestFuncs_Now(t *testing.T) {
+	got, err := render(t, `{{now | date "2006"}}`, nil)
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if want := time.Now().Format("2006"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestFuncs_Errors verifies invalid input fails the template.
+func TestFuncs_Errors(t *testing.T) {
+	tests := []string{
+		`{{"abc" | add 1}}`,
+		`{{1 | div 0}}`,
+		`{{"not json" | fromJson}}`,
+		`{{"yesterday" | date "2006"}}`,
+		`{{"x" | regexMatch "["}}`,
+	}
+
+	for _, tmpl := range tests {
+		t.Run(tmpl, func(t *testing.T) {
+			if _, err := render(t, tmpl, nil); err == nil {
+				t.Errorf("expected error for %s", tmpl)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package furikake
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// toNumber converts a template value to a number. The bool result reports
+// whether it is an integer. Numeric strings (e.g. CSV cells) are accepted.
+func toNumber(v interface{}) (float64, bool, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true, nil
+	case int32:
+		return float64(n), true, nil
+	case int64:
+		return float64(n), true, nil
+	case float32:
+		return float64(n), false, nil
+	case float64:
+		// JSON numbers are always float64; whole values count as integers.
+		return n, n == math.Trunc(n) && math.Abs(n) < 1<<53, nil
+	case string:
+		s := strings.TrimSpace(n)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return float64(i), true, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("not a number: %q", n)
+		}
+		return f, false, nil
+	default:
+		return 0, false, fmt.Errorf("not a number: %v (%T)", v, v)
+	}
+}
+
+// number returns n as an int when isInt, otherwise as a float64.
+func number(n float64, isInt bool) interface{} {
+	if isInt {
+		return int(n)
+	}
+	return n
+}
+
+// arithmetic applies op to a and b. The result is an int when both operands
+// are integers, otherwise a float64.
+func arithmetic(a, b interface{}, op func(x, y float64) float64) (interface{}, error) {
+	x, xInt, err := toNumber(a)
+	if err != nil {
+		return nil, err
+	}
+	y, yInt, err := toNumber(b)
+	if err != nil {
+		return nil, err
+	}
+	return number(op(x, y), xInt && yInt), nil
+}
+
+// add returns b + a so pipes read naturally: {{.index | add 1}}.
+func add(a, b interface{}) (interface{}, error) {
+	return arithmetic(b, a, func(x, y float64) float64 { return x + y })
+}
+
+// sub returns b - a: {{.count | sub 1}}.
+func sub(a, b interface{}) (interface{}, error) {
+	return arithmetic(b, a, func(x, y float64) float64 { return x - y })
+}
+
+// mul returns b * a: {{.price | mul 2}}.
+func mul(a, b interface{}) (interface{}, error) {
+	return arithmetic(b, a, func(x, y float64) float64 { return x * y })
+}
+
+// div returns b / a: {{.total | div 2}}. Integer operands divide as integers.
+func div(a, b interface{}) (interface{}, error) {
+	x, xInt, err := toNumber(b)
+	if err != nil {
+		return nil, err
+	}
+	y, yInt, err := toNumber(a)
+	if err != nil {
+		return nil, err
+	}
+	if y == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	if xInt && yInt {
+		return int(x) / int(y), nil
+	}
+	return x / y, nil
+}
+
+// mod returns b % a: {{.index | mod 2}}.
+func mod(a, b interface{}) (interface{}, error) {
+	x, _, err := toNumber(b)
+	if err != nil {
+		return nil, err
+	}
+	y, _, err := toNumber(a)
+	if err != nil {
+		return nil, err
+	}
+	if int(y) == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return int(x) % int(y), nil
+}
+
+// minimum returns the smallest of its arguments.
+func minimum(first interface{}, rest ...interface{}) (interface{}, error) {
+	return pick(first, rest, func(x, y float64) bool { return x < y })
+}
+
+// maximum returns the largest of its arguments.
+func maximum(first interface{}, rest ...interface{}) (interface{}, error) {
+	return pick(first, rest, func(x, y float64) bool { return x > y })
+}
+
+// pick returns the argument that wins every better comparison.
+func pick(first interface{}, rest []interface{}, better func(x, y float64) bool) (interface{}, error) {
+	best, bestInt, err := toNumber(first)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range rest {
+		n, isInt, err := toNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		if better(n, best) {
+			best, bestInt = n, isInt
+		}
+	}
+	return number(best, bestInt), nil
+}
+
+// round rounds to the given number of decimal places: {{.price | round 2}}.
+func round(places int, v interface{}) (float64, error) {
+	n, _, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	scale := math.Pow(10, float64(places))
+	return math.Round(n*scale) / scale, nil
+}
+
+// floor rounds down to an integer.
+func floor(v interface{}) (int, error) {
+	n, _, err := toNumber(v)
+	return int(math.Floor(n)), err
+}
+
+// ceil rounds up to an integer.
+func ceil(v interface{}) (int, error) {
+	n, _, err := toNumber(v)
+	return int(math.Ceil(n)), err
+}
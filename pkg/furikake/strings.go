@@ -0,0 +1,182 @@
+package furikake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// toString converts a template value to a string (nil becomes "").
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func lower(s interface{}) string { return strings.ToLower(toString(s)) }
+func upper(s interface{}) string { return strings.ToUpper(toString(s)) }
+func trim(s interface{}) string  { return strings.TrimSpace(toString(s)) }
+
+func trimPrefix(prefix string, s interface{}) string {
+	return strings.TrimPrefix(toString(s), prefix)
+}
+
+func trimSuffix(suffix string, s interface{}) string {
+	return strings.TrimSuffix(toString(s), suffix)
+}
+
+func replace(old, new string, s interface{}) string {
+	return strings.ReplaceAll(toString(s), old, new)
+}
+
+func contains(substr string, s interface{}) bool {
+	return strings.Contains(toString(s), substr)
+}
+
+func hasPrefix(prefix string, s interface{}) bool {
+	return strings.HasPrefix(toString(s), prefix)
+}
+
+func hasSuffix(suffix string, s interface{}) bool {
+	return strings.HasSuffix(toString(s), suffix)
+}
+
+// split splits a string into a list: {{.path | split "/"}}.
+func split(sep string, s interface{}) []interface{} {
+	parts := strings.Split(toString(s), sep)
+	list := make([]interface{}, len(parts))
+	for i, part := range parts {
+		list[i] = part
+	}
+	return list
+}
+
+func regexMatch(pattern string, s interface{}) (bool, error) {
+	return regexp.MatchString(pattern, toString(s))
+}
+
+// regexFind returns the first match of pattern, or "".
+func regexFind(pattern string, s interface{}) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.FindString(toString(s)), nil
+}
+
+// regexReplace replaces all matches of pattern; repl may use $1 etc.
+func regexReplace(pattern, repl string, s interface{}) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+	return re.ReplaceAllString(toString(s), repl), nil
+}
+
+// padLeft pads s on the left to width with pad: {{.index | padLeft 3 "0"}}.
+func padLeft(width int, pad string, s interface{}) string {
+	str := toString(s)
+	return padding(width-len([]rune(str)), pad) + str
+}
+
+// padRight pads s on the right to width with pad.
+func padRight(width int, pad string, s interface{}) string {
+	str := toString(s)
+	return str + padding(width-len([]rune(str)), pad)
+}
+
+// padding returns n runes of pad repeated (empty when n <= 0).
+func padding(n int, pad string) string {
+	if n <= 0 || pad == "" {
+		return ""
+	}
+	runes := []rune(strings.Repeat(pad, n))
+	return string(runes[:n])
+}
+
+// words splits a string into words at separators and lower-to-upper case
+// changes ("productSKU_id" -> product, SKU, id).
+func words(s string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(s)
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return result
+}
+
+// title capitalizes the first letter of each word, keeping separators.
+func title(s interface{}) string {
+	runes := []rune(toString(s))
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '-' || runes[i-1] == '_' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
+
+// camel converts to camelCase: "product name" -> "productName".
+func camel(s interface{}) string {
+	var b strings.Builder
+	for i, word := range words(toString(s)) {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// snake converts to snake_case: "Product Name" -> "product_name".
+func snake(s interface{}) string {
+	return strings.ToLower(strings.Join(words(toString(s)), "_"))
+}
+
+// kebab converts to kebab-case: "Product Name" -> "product-name".
+func kebab(s interface{}) string {
+	return strings.ToLower(strings.Join(words(toString(s)), "-"))
+}
+
+// slug converts to a URL and file name safe slug: "Combat Dog (Supplies)" -> "combat-dog-supplies".
+func slug(s interface{}) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(toString(s)) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package furikake
+
+import (
+	"fmt"
+	"time"
+)
+
+// now returns the current time.
+func now() time.Time {
+	return time.Now()
+}
+
+// date formats a time with a Go layout: {{now | date "2006-01-02"}}.
+//
+// The value may be a time.Time, an RFC 3339 string, or Unix seconds.
+func date(layout string, v interface{}) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// toTime converts a template value to a time.
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	case int:
+		return time.Unix(int64(t), 0), nil
+	case int64:
+		return time.Unix(t, 0), nil
+	case float64:
+		return time.Unix(int64(t), 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("not a time: %v (%T)", v, v)
+	}
+}
@@ -0,0 +1,108 @@
+package furikake
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// empty reports whether a value is unset: nil, false, zero, or an empty
+// string, list, or map.
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// defaultValue returns def when v is empty: {{.item.color | default "red"}}.
+func defaultValue(def, v interface{}) interface{} {
+	if empty(v) {
+		return def
+	}
+	return v
+}
+
+// coalesce returns the first non-empty value, or nil.
+func coalesce(values ...interface{}) interface{} {
+	for _, v := range values {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// toJSON encodes a value as JSON.
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// fromJSON decodes a JSON string: {{(.raw | fromJson).name}}.
+func fromJSON(s interface{}) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(toString(s)), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// toList converts a slice or array of any element type to []interface{}.
+// Other values become a single-element list; nil becomes empty.
+func toList(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+	list := make([]interface{}, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list
+}
+
+// join joins list elements with sep: {{.tags | join ", "}}.
+func join(sep string, v interface{}) string {
+	list := toList(v)
+	parts := make([]string, len(list))
+	for i, item := range list {
+		parts[i] = toString(item)
+	}
+	return strings.Join(parts, sep)
+}
+
+// first returns the first list element, or nil when the list is empty.
+func first(v interface{}) interface{} {
+	list := toList(v)
+	if len(list) == 0 {
+		return nil
+	}
+	return list[0]
+}
+
+// last returns the last list element, or nil when the list is empty.
+func last(v interface{}) interface{} {
+	list := toList(v)
+	if len(list) == 0 {
+		return nil
+	}
+	return list[len(list)-1]
+}
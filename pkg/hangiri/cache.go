@@ -0,0 +1,167 @@
+package hangiri
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheStore stores content-addressed node outputs in ~/.bento/cache/
+//
+// Each entry is a JSON file named after its key (a hex content hash
+// computed by the itamae). CacheStore satisfies itamae.OutputCache.
+type CacheStore struct {
+	dir string
+}
+
+// CacheEntry is a cached node output.
+type CacheEntry struct {
+	Key       string      `json:"key"`       // Content hash
+	NodeID    string      `json:"nodeId"`    // Node that produced the output
+	NodeType  string      `json:"nodeType"`  // Neta type of the node
+	CreatedAt time.Time   `json:"createdAt"` // When the output was cached
+	Output    interface{} `json:"output"`    // The cached output
+	Size      int64       `json:"-"`         // Size on disk in bytes
+}
+
+// Cache returns the cache store in the cache storage directory.
+func (s *Storage) Cache() *CacheStore {
+	return &CacheStore{dir: s.getStorageDir(StorageTypeCache)}
+}
+
+// getEntryPath returns the file path for a cache key.
+func (c *CacheStore) getEntryPath(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// Get returns the cached output for key, if present.
+func (c *CacheStore) Get(key string) (interface{}, bool) {
+	if !isCacheKey(key) {
+		return nil, false
+	}
+
+	entry, err := readCacheEntry(c.getEntryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	return entry.Output, true
+}
+
+// Put stores the output of a node under key.
+func (c *CacheStore) Put(key, nodeID, nodeType string, output interface{}) error {
+	if !isCacheKey(key) {
+		return fmt.Errorf("invalid cache key '%s'", key)
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(CacheEntry{
+		Key:       key,
+		NodeID:    nodeID,
+		NodeType:  nodeType,
+		CreatedAt: time.Now(),
+		Output:    output,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize output of '%s': %w", nodeID, err)
+	}
+
+	// Write atomically so a concurrent reader never sees a partial entry
+	path := c.getEntryPath(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns all cache entries, newest first.
+func (c *CacheStore) List() ([]CacheEntry, error) {
+	files, err := c.entryFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]CacheEntry, 0, len(files))
+	for _, path := range files {
+		entry, err := readCacheEntry(path)
+		if err != nil {
+			continue // Skip unreadable entries
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// Clear removes all cache entries and returns how many were removed.
+func (c *CacheStore) Clear() (int, error) {
+	files, err := c.entryFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, path := range files {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+	}
+	return len(files), nil
+}
+
+// entryFiles returns the paths of all cache entry files.
+func (c *CacheStore) entryFiles() ([]string, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list cache: %w", err)
+	}
+
+	var files []string
+	for _, entry := range dirEntries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".json") && isCacheKey(strings.TrimSuffix(name, ".json")) {
+			files = append(files, filepath.Join(c.dir, name))
+		}
+	}
+	return files, nil
+}
+
+// readCacheEntry reads and parses a cache entry file.
+func readCacheEntry(path string) (*CacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	entry.Size = int64(len(data))
+	return &entry, nil
+}
+
+// isCacheKey checks that key is a lowercase hex hash (prevents path traversal).
+func isCacheKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -380,3 +380,44 @@ func TestStorage_DeleteNotFound(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
+
+// Test: Cache entries round-trip and can be cleared
+func TestCacheStore_PutGetClear(t *testing.T) {
+	cache := hangiri.New(t.TempDir()).Cache()
+	key := "a1b2c3"
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("Expected miss on empty cache")
+	}
+
+	output := map[string]interface{}{"path": "render.png"}
+	if err := cache.Put(key, "render", "shell-command", output); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	cached, ok := cache.Get(key)
+	if !ok || !reflect.DeepEqual(cached, output) {
+		t.Errorf("Get = %v, %v; want %v", cached, ok, output)
+	}
+
+	entries, err := cache.List()
+	if err != nil || len(entries) != 1 || entries[0].NodeID != "render" {
+		t.Fatalf("List = %v, %v; want one entry for 'render'", entries, err)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 1 {
+		t.Fatalf("Clear = %d, %v; want 1", removed, err)
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Error("Expected miss after Clear")
+	}
+}
+
+// Test: Keys that aren't hex hashes are rejected
+func TestCacheStore_InvalidKey(t *testing.T) {
+	cache := hangiri.New(t.TempDir()).Cache()
+	if err := cache.Put("../escape", "n", "t", "x"); err == nil {
+		t.Error("Expected error for invalid cache key")
+	}
+}
@@ -0,0 +1,120 @@
+package itamae_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/neta/library/branch"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// TestItamae_IfBranchRouting tests that only the active handle's subgraph runs.
+func TestItamae_IfBranchRouting(t *testing.T) {
+	ctx := context.Background()
+
+	// check -> (true) overlay -> finish
+	//       -> (false) plain -> finish
+	bento := &neta.Definition{
+		ID:   "branch-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{ID: "check", Type: "if", Parameters: map[string]interface{}{"condition": "1 > 2"}},
+			{ID: "overlay", Type: "counter", Parameters: map[string]interface{}{"value": "overlay"}},
+			{ID: "overlay-save", Type: "counter", Parameters: map[string]interface{}{"value": "saved"}},
+			{ID: "plain", Type: "counter", Parameters: map[string]interface{}{"value": "plain"}},
+			{ID: "finish", Type: "counter", Parameters: map[string]interface{}{"value": "done"}},
+		},
+		Edges: []neta.Edge{
+			{ID: "e1", Source: "check", Target: "overlay", SourceHandle: "true"},
+			{ID: "e2", Source: "check", Target: "plain", SourceHandle: "false"},
+			{ID: "e3", Source: "overlay", Target: "overlay-save"},
+			{ID: "e4", Source: "overlay-save", Target: "finish"},
+			{ID: "e5", Source: "plain", Target: "finish"},
+		},
+	}
+
+	calls := 0
+	noFail := false
+	p := pantry.New()
+	p.RegisterFactory("if", func() neta.Executable { return branch.NewIf() })
+	p.RegisterFactory("counter", func() neta.Executable {
+		return &countingNeta{calls: &calls, fail: &noFail}
+	})
+
+	result, err := itamae.New(p, nil).Serve(ctx, bento)
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("counter calls = %d, want 2 (plain and finish)", calls)
+	}
+	if result.NodesSkipped != 2 {
+		t.Errorf("NodesSkipped = %d, want 2", result.NodesSkipped)
+	}
+	if _, ok := result.NodeOutputs["overlay"]; ok {
+		t.Error("overlay should not have executed")
+	}
+	if _, ok := result.NodeOutputs["finish"]; !ok {
+		t.Error("finish should run when any incoming branch is active")
+	}
+	if result.Status != itamae.StatusSuccess {
+		t.Errorf("Status = %v, want %v", result.Status, itamae.StatusSuccess)
+	}
+}
+
+// TestItamae_SwitchBranchFallback tests that a switch selecting no known
+// handle, or skipped by when, activates only the "default" handle.
+func TestItamae_SwitchBranchFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		when       string
+		want       string
+	}{
+		{name: "matching handle", expression: "'poster'", want: "poster"},
+		{name: "empty handle", expression: "''", want: "fallback"},
+		{name: "unknown handle", expression: "'banner'", want: "fallback"},
+		{name: "skipped by when", expression: "'poster'", when: "false", want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		noFail := false
+		p := pantry.New()
+		p.RegisterFactory("switch", func() neta.Executable { return branch.NewSwitch() })
+		p.RegisterFactory("counter", func() neta.Executable {
+			return &countingNeta{calls: &calls, fail: &noFail}
+		})
+
+		result, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+			ID:   "switch-bento",
+			Type: "group",
+			Nodes: []neta.Definition{
+				{ID: "route", Type: "switch", When: tt.when, Parameters: map[string]interface{}{
+					"expression": tt.expression,
+				}},
+				{ID: "poster", Type: "counter"},
+				{ID: "render", Type: "counter"},
+				{ID: "fallback", Type: "counter"},
+			},
+			Edges: []neta.Edge{
+				{ID: "e1", Source: "route", Target: "poster", SourceHandle: "poster"},
+				{ID: "e2", Source: "route", Target: "render", SourceHandle: "render"},
+				{ID: "e3", Source: "route", Target: "fallback", SourceHandle: branch.DefaultHandle},
+			},
+		})
+		if err != nil {
+			t.Fatalf("%s: Serve failed: %v", tt.name, err)
+		}
+
+		if calls != 1 {
+			t.Errorf("%s: counter calls = %d, want 1", tt.name, calls)
+		}
+		if _, ok := result.NodeOutputs[tt.want]; !ok {
+			t.Errorf("%s: %s did not run, outputs: %v", tt.name, tt.want, result.NodeOutputs)
+		}
+	}
+}
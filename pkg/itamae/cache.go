@@ -0,0 +1,121 @@
+package itamae
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// OutputCache stores node outputs under content-addressed keys.
+// Implemented by hangiri's cache store; outputs must be JSON-serializable.
+type OutputCache interface {
+	// Get returns the cached output for key, if present.
+	Get(key string) (interface{}, bool)
+
+	// Put stores the output of a node under key.
+	Put(key, nodeID, nodeType string, output interface{}) error
+}
+
+// contextReaders are the neta types that compute their output from _context
+// (expressions and templates over the whole context) rather than only from
+// their parameters.
+var contextReaders = map[string]bool{
+	"transform":   true,
+	"edit-fields": true,
+	"if":          true,
+	"switch":      true,
+}
+
+// cacheKey hashes a node's type, its resolved parameters and the contents of
+// any existing files its parameters reference.
+//
+// Internal parameters (_context, _onOutput) are excluded, so unrelated context
+// data doesn't invalidate the key - only what the node is actually given
+// (including its named _inputs). Context readers are given the whole
+// context, so for them _context is part of the key.
+func cacheKey(def *neta.Definition, params map[string]interface{}) (string, error) {
+	userParams := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if !strings.HasPrefix(k, "_") || k == "_inputs" {
+			userParams[k] = v
+		}
+	}
+
+	files := make(map[string]string)
+	if err := hashReferencedFiles(userParams, files); err != nil {
+		return "", err
+	}
+
+	key := map[string]interface{}{
+		"type":    def.Type,
+		"version": def.Version,
+		"params":  userParams,
+		"files":   files,
+	}
+	if contextReaders[def.Type] {
+		key["context"] = params["_context"]
+	}
+
+	payload, err := json.Marshal(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cache key: %w", err)
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashReferencedFiles records a content hash for every string value that names an existing file.
+func hashReferencedFiles(value interface{}, files map[string]string) error {
+	switch v := value.(type) {
+	case string:
+		return hashFileIfExists(v, files)
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := hashReferencedFiles(item, files); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := hashReferencedFiles(item, files); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hashFileIfExists hashes path if it's a regular file.
+func hashFileIfExists(path string, files map[string]string) error {
+	if path == "" || strings.ContainsRune(path, '\n') {
+		return nil
+	}
+	if _, done := files[path]; done {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read input file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to hash input file %s: %w", path, err)
+	}
+	files[path] = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
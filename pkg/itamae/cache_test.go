@@ -0,0 +1,183 @@
+package itamae_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/neta/library/transform"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// memoryCache is an in-memory OutputCache.
+type memoryCache map[string]interface{}
+
+func (m memoryCache) Get(key string) (interface{}, bool) {
+	output, ok := m[key]
+	return output, ok
+}
+
+func (m memoryCache) Put(key, nodeID, nodeType string, output interface{}) error {
+	m[key] = output
+	return nil
+}
+
+// runCachedNode serves a cached counting node that reads the given file.
+func runCachedNode(t *testing.T, cache memoryCache, path string, calls *int) *itamae.Result {
+	t.Helper()
+
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: calls, output: map[string]interface{}{"ok": true}}
+	})
+
+	chef := itamae.New(p, nil)
+	chef.SetCache(cache)
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:         "render",
+		Type:       "flaky",
+		Name:       "Render",
+		Cache:      true,
+		Parameters: map[string]interface{}{"input": path},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	return result
+}
+
+// TestItamae_CacheHit tests that an unchanged node is served from the cache.
+func TestItamae_CacheHit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.blend")
+	if err := os.WriteFile(path, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := memoryCache{}
+	calls := 0
+	runCachedNode(t, cache, path, &calls)
+	result := runCachedNode(t, cache, path, &calls)
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if result.NodesCached != 1 {
+		t.Errorf("NodesCached = %d, want 1", result.NodesCached)
+	}
+	if result.NodeOutputs["render"] == nil {
+		t.Error("Expected cached output in NodeOutputs")
+	}
+}
+
+// TestItamae_CacheInvalidatedByFile tests that changing an input file misses the cache.
+func TestItamae_CacheInvalidatedByFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.blend")
+	if err := os.WriteFile(path, []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := memoryCache{}
+	calls := 0
+	runCachedNode(t, cache, path, &calls)
+
+	if err := os.WriteFile(path, []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result := runCachedNode(t, cache, path, &calls)
+
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if result.NodesCached != 0 {
+		t.Errorf("NodesCached = %d, want 0", result.NodesCached)
+	}
+}
+
+// TestItamae_CacheContextReader tests that nodes computing their output from
+// the context don't reuse another iteration's cached output.
+func TestItamae_CacheContextReader(t *testing.T) {
+	p := pantry.New()
+	p.RegisterFactory("transform", func() neta.Executable { return transform.New() })
+
+	chef := itamae.New(p, nil)
+	chef.SetCache(memoryCache{})
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "scale",
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode":  "forEach",
+			"items": []interface{}{1, 2, 3},
+		},
+		Nodes: []neta.Definition{{
+			ID:         "times-ten",
+			Type:       "transform",
+			Cache:      true,
+			Parameters: map[string]interface{}{"expression": "item * 10"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	for idx, iteration := range result.NodeOutputs["scale"].([]interface{}) {
+		output := iteration.(map[string]interface{})["times-ten"].(map[string]interface{})
+		if want := (idx + 1) * 10; output["result"] != want {
+			t.Errorf("iteration %d: result = %v, want %d", idx, output["result"], want)
+		}
+	}
+}
+
+// TestItamae_CacheHitsCounted tests that cache hits in parallel branches and
+// loop iterations count in the result.
+func TestItamae_CacheHitsCounted(t *testing.T) {
+	renders, previews := 0, 0
+	p := pantry.New()
+	p.RegisterFactory("render", func() neta.Executable {
+		return &flakyNeta{calls: &renders, output: map[string]interface{}{"ok": true}}
+	})
+	p.RegisterFactory("preview", func() neta.Executable {
+		return &flakyNeta{calls: &previews, output: map[string]interface{}{"ok": true}}
+	})
+
+	chef := itamae.New(p, nil)
+	chef.SetCache(memoryCache{})
+	def := &neta.Definition{
+		ID:   "renders",
+		Type: "parallel",
+		Nodes: []neta.Definition{
+			{
+				ID:   "products",
+				Type: "loop",
+				Parameters: map[string]interface{}{
+					"mode":  "forEach",
+					"items": []interface{}{"A", "B", "C"},
+				},
+				Nodes: []neta.Definition{{ID: "render", Type: "render", Cache: true}},
+			},
+			{ID: "preview", Type: "preview", Cache: true},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		cached int
+	}{
+		{name: "first run", cached: 2}, // The first iteration renders, the others reuse it
+		{name: "second run", cached: 4},
+	}
+	for _, tt := range tests {
+		result, err := chef.Serve(context.Background(), def)
+		if err != nil {
+			t.Fatalf("%s: Serve failed: %v", tt.name, err)
+		}
+		if result.NodesCached != tt.cached {
+			t.Errorf("%s: NodesCached = %d, want %d", tt.name, result.NodesCached, tt.cached)
+		}
+	}
+	if renders != 1 || previews != 1 {
+		t.Errorf("renders/previews = %d/%d, want 1/1", renders, previews)
+	}
+}
@@ -0,0 +1,89 @@
+package itamae
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// runKey is the context key holding the context a run was served with.
+//
+// Nodes run on a context that is only cancelled once the grace period after
+// the run's own context is done, so the run's context is kept alongside it:
+// once it is done no new node starts, while in-flight nodes may finish.
+type runKey struct{}
+
+// withGracePeriod returns the context nodes run with.
+//
+// When the run's context is done, in-flight nodes keep running for the
+// grace period before their context is cancelled too. Without a grace
+// period they are cancelled right away.
+func (i *Itamae) withGracePeriod(ctx context.Context) (context.Context, context.CancelFunc) {
+	if i.gracePeriod <= 0 {
+		return context.WithValue(ctx, runKey{}, ctx), func() {}
+	}
+
+	nodeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-nodeCtx.Done():
+			return
+		}
+
+		timer := time.NewTimer(i.gracePeriod)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-nodeCtx.Done():
+		}
+	}()
+	return context.WithValue(nodeCtx, runKey{}, ctx), cancel
+}
+
+// runContext returns the context the run was served with (ctx outside a run).
+func runContext(ctx context.Context) context.Context {
+	if run, ok := ctx.Value(runKey{}).(context.Context); ok {
+		return run
+	}
+	return ctx
+}
+
+// stopErr returns why no new node may start: ctx's error, or the run's once
+// it is cancelled or timed out. Returns nil while the run goes on.
+func stopErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return runContext(ctx).Err()
+}
+
+// runCancelled reports whether the run was cancelled (not timed out).
+func runCancelled(ctx context.Context) bool {
+	return errors.Is(runContext(ctx).Err(), context.Canceled)
+}
+
+// cleanupContext returns the context onCancel and finally nodes run with.
+//
+// Once the run is stopped they run detached from its cancellation, so
+// cleanup happens even after Ctrl+C. Otherwise ctx is returned as it is.
+func cleanupContext(ctx context.Context) context.Context {
+	if runContext(ctx).Err() == nil {
+		return ctx
+	}
+	return context.WithValue(context.WithoutCancel(ctx), runKey{}, nil)
+}
+
+// withCancelScope returns a context for nodes that are cancelled together,
+// like the children of a failFast parallel. Cancelling the scope stops its
+// nodes as if the run was cancelled: no new node starts, in-flight nodes
+// are cancelled and their groups run onCancel and finally nodes.
+func withCancelScope(ctx context.Context) (context.Context, context.CancelFunc) {
+	run, cancelRun := context.WithCancel(runContext(ctx))
+	nodeCtx, cancelNodes := context.WithCancel(ctx)
+	return context.WithValue(nodeCtx, runKey{}, run), func() {
+		cancelRun()
+		cancelNodes()
+	}
+}
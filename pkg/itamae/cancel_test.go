@@ -0,0 +1,117 @@
+package itamae_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// serveCancelled runs a group of sleepy nodes with onCancel and finally
+// nodes and cancels the run after 50ms.
+func serveCancelled(t *testing.T, gracePeriod time.Duration, nodes []neta.Definition) (
+	*itamae.Result, map[string][]map[string]interface{}, error) {
+	t.Helper()
+
+	calls := make(map[string][]map[string]interface{})
+	p := newSleepyPantry(make(chan struct{}, len(nodes)))
+	p.RegisterFactory("notify", func() neta.Executable { return &recordNeta{nodeType: "notify", calls: calls} })
+	p.RegisterFactory("cleanup", func() neta.Executable { return &recordNeta{nodeType: "cleanup", calls: calls} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	chef := itamae.New(p, nil)
+	chef.SetGracePeriod(gracePeriod)
+	result, err := chef.Serve(ctx, &neta.Definition{
+		ID:       "renders",
+		Type:     "group",
+		Name:     "Renders",
+		Nodes:    nodes,
+		Edges:    []neta.Edge{{ID: "e1", Source: "render", Target: "upload"}},
+		OnCancel: []neta.Definition{{ID: "notify", Type: "notify", Name: "Post Cancelled"}},
+		Finally:  []neta.Definition{cleanupNode},
+	})
+	return result, calls, err
+}
+
+// sleepyNodes returns a render node that takes renderDelay and an upload node after it.
+func sleepyNodes(renderDelay string) []neta.Definition {
+	return []neta.Definition{
+		{ID: "render", Type: "sleepy", Parameters: map[string]interface{}{"delay": renderDelay, "value": "render.png"}},
+		{ID: "upload", Type: "sleepy", Parameters: map[string]interface{}{"delay": "0s", "value": "uploaded"}},
+	}
+}
+
+// TestItamae_CancelRunsCleanup tests that cancelling a run stops it with
+// StatusCancelled and still runs the onCancel and finally nodes.
+func TestItamae_CancelRunsCleanup(t *testing.T) {
+	result, calls, err := serveCancelled(t, 0, sleepyNodes("5s"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if result.Status != itamae.StatusCancelled {
+		t.Errorf("Status = %q, want %q", result.Status, itamae.StatusCancelled)
+	}
+	if _, ok := result.NodeOutputs["upload"]; ok {
+		t.Error("upload should not start after cancellation")
+	}
+	if len(calls["notify"]) != 1 {
+		t.Errorf("onCancel calls = %d, want 1", len(calls["notify"]))
+	}
+	if len(calls["cleanup"]) != 1 {
+		t.Errorf("finally calls = %d, want 1", len(calls["cleanup"]))
+	}
+}
+
+// TestItamae_CancelGracePeriod tests that in-flight nodes may finish within
+// the grace period while no new node starts.
+func TestItamae_CancelGracePeriod(t *testing.T) {
+	result, calls, err := serveCancelled(t, time.Second, sleepyNodes("200ms"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if result.Status != itamae.StatusCancelled {
+		t.Errorf("Status = %q, want %q", result.Status, itamae.StatusCancelled)
+	}
+	if _, ok := result.NodeOutputs["render"]; !ok {
+		t.Error("in-flight render should finish within the grace period")
+	}
+	if _, ok := result.NodeOutputs["upload"]; ok {
+		t.Error("upload should not start after cancellation")
+	}
+	if len(calls["notify"]) != 1 || len(calls["cleanup"]) != 1 {
+		t.Errorf("cleanup calls = %v, want onCancel and finally once", calls)
+	}
+}
+
+// TestItamae_CancelGracePeriodExpires tests that in-flight nodes are
+// cancelled once the grace period is over.
+func TestItamae_CancelGracePeriodExpires(t *testing.T) {
+	start := time.Now()
+	result, _, err := serveCancelled(t, 100*time.Millisecond, sleepyNodes("5s"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Serve took %v, grace period was not enforced", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) || result.Status != itamae.StatusCancelled {
+		t.Errorf("Serve = %q, %v; want cancelled", result.Status, err)
+	}
+}
+
+// TestItamae_OnCancelSkippedOnSuccess tests that onCancel nodes don't run
+// when the group finishes.
+func TestItamae_OnCancelSkippedOnSuccess(t *testing.T) {
+	_, calls, err := serveCancelled(t, 0, sleepyNodes("0s"))
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if len(calls["notify"]) != 0 {
+		t.Errorf("onCancel calls = %d, want 0", len(calls["notify"]))
+	}
+	if len(calls["cleanup"]) != 1 {
+		t.Errorf("finally calls = %d, want 1", len(calls["cleanup"]))
+	}
+}
@@ -0,0 +1,272 @@
+package itamae
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Checkpoint persists completed node outputs so an interrupted run can resume.
+//
+// Layout on disk (one JSON file per completed unit of work):
+//
+//	<dir>/
+//	  nodes/<node-id>.json              - Output of a completed node or loop
+//	  iterations/<loop-id>/<index>.json - Output of a finished loop iteration
+//
+// Each file holds the output along with a hash of what produced it: the
+// node's definition, or the loop's definition and the iteration's item.
+// Outputs whose hash doesn't match on resume are not restored, so edited
+// nodes and reordered or changed loop items run again.
+//
+// Checkpoint is safe for concurrent use (concurrent loop iterations write
+// their own files).
+type Checkpoint struct {
+	dir        string
+	mu         sync.RWMutex
+	nodes      map[string]checkpointEntry
+	iterations map[string]map[int]checkpointEntry
+}
+
+// checkpointEntry is a checkpointed output and the hash of what produced it.
+type checkpointEntry struct {
+	Hash   string      `json:"hash"`
+	Output interface{} `json:"output"`
+}
+
+// NewCheckpoint creates an empty checkpoint that writes to dir.
+// The directory is created if it doesn't exist.
+func NewCheckpoint(dir string) (*Checkpoint, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &Checkpoint{
+		dir:        dir,
+		nodes:      make(map[string]checkpointEntry),
+		iterations: make(map[string]map[int]checkpointEntry),
+	}, nil
+}
+
+// LoadCheckpoint loads a previously written checkpoint from dir.
+// New outputs are written back to the same directory.
+func LoadCheckpoint(dir string) (*Checkpoint, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("checkpoint not found: %w", err)
+	}
+
+	cp, err := NewCheckpoint(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cp.loadNodes(); err != nil {
+		return nil, err
+	}
+	if err := cp.loadIterations(); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// Dir returns the checkpoint directory.
+func (c *Checkpoint) Dir() string {
+	return c.dir
+}
+
+// NodeCount returns the number of checkpointed nodes.
+func (c *Checkpoint) NodeCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.nodes)
+}
+
+// loadNodes reads all node outputs from <dir>/nodes.
+func (c *Checkpoint) loadNodes() error {
+	files, err := readJSONFiles(filepath.Join(c.dir, "nodes"))
+	if err != nil {
+		return err
+	}
+	for name, entry := range files {
+		c.nodes[name] = entry
+	}
+	return nil
+}
+
+// loadIterations reads all loop iteration outputs from <dir>/iterations.
+func (c *Checkpoint) loadIterations() error {
+	root := filepath.Join(c.dir, "iterations")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read checkpoint iterations: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		loopID, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+		files, err := readJSONFiles(filepath.Join(root, entry.Name()))
+		if err != nil {
+			return err
+		}
+		c.iterations[loopID] = make(map[int]checkpointEntry)
+		for name, entry := range files {
+			idx, err := strconv.Atoi(name)
+			if err != nil {
+				continue
+			}
+			c.iterations[loopID][idx] = entry
+		}
+	}
+	return nil
+}
+
+// readJSONFiles reads every *.json file in dir, keyed by unescaped base name.
+func readJSONFiles(dir string) (map[string]checkpointEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]checkpointEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read checkpoint directory: %w", err)
+	}
+
+	files := make(map[string]checkpointEntry)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		name, err := url.PathUnescape(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint file %s: %w", entry.Name(), err)
+		}
+		var checkpointed checkpointEntry
+		if err := json.Unmarshal(data, &checkpointed); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint file %s: %w", entry.Name(), err)
+		}
+		files[name] = checkpointed
+	}
+	return files, nil
+}
+
+// node returns the checkpointed output for a node, if it was produced by a
+// definition with the same hash.
+func (c *Checkpoint) node(nodeID, hash string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.nodes[nodeID]
+	if !ok || entry.Hash != hash {
+		return nil, false
+	}
+	return entry.Output, true
+}
+
+// iteration returns the checkpointed output for a loop iteration, if it was
+// produced by a loop and item with the same hash.
+func (c *Checkpoint) iteration(loopID string, idx int, hash string) (map[string]interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.iterations[loopID][idx]
+	if !ok || entry.Hash != hash {
+		return nil, false
+	}
+	m, ok := entry.Output.(map[string]interface{})
+	return m, ok
+}
+
+// saveNode persists the output of a completed node.
+func (c *Checkpoint) saveNode(nodeID, hash string, output interface{}) error {
+	entry := checkpointEntry{Hash: hash, Output: output}
+	path := filepath.Join(c.dir, "nodes", url.PathEscape(nodeID)+".json")
+	if err := writeJSONFile(path, entry); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.nodes[nodeID] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// saveIteration persists the output of a finished loop iteration.
+func (c *Checkpoint) saveIteration(loopID string, idx int, hash string, output map[string]interface{}) error {
+	entry := checkpointEntry{Hash: hash, Output: output}
+	path := filepath.Join(c.dir, "iterations", url.PathEscape(loopID), strconv.Itoa(idx)+".json")
+	if err := writeJSONFile(path, entry); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.iterations[loopID] == nil {
+		c.iterations[loopID] = make(map[int]checkpointEntry)
+	}
+	c.iterations[loopID][idx] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+// definitionHash hashes a node's definition. Its position and metadata
+// don't change what it produces, so they are left out.
+func definitionHash(def *neta.Definition) (string, error) {
+	trimmed := *def
+	trimmed.Position = neta.Position{}
+	trimmed.Metadata = neta.Metadata{}
+	return hashJSON(trimmed)
+}
+
+// iterationHash hashes a loop's definition and the item of one iteration.
+func iterationHash(def *neta.Definition, item interface{}) (string, error) {
+	defHash, err := definitionHash(def)
+	if err != nil {
+		return "", err
+	}
+	return hashJSON(map[string]interface{}{"definition": defHash, "item": item})
+}
+
+// hashJSON returns the SHA-256 of value's JSON encoding.
+func hashJSON(value interface{}) (string, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode checkpoint hash: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// writeJSONFile writes data atomically (temp file + rename) so a crash
+// mid-write never leaves a truncated checkpoint behind.
+func writeJSONFile(path string, data interface{}) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, encoded, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,242 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// countingNeta records how often it runs and optionally fails.
+type countingNeta struct {
+	calls *int
+	fail  *bool
+}
+
+func (c *countingNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	*c.calls++
+	if *c.fail {
+		return nil, fmt.Errorf("simulated failure")
+	}
+	return map[string]interface{}{"value": params["value"]}, nil
+}
+
+// TestItamae_ResumeFromCheckpoint tests that completed nodes are skipped on resume.
+func TestItamae_ResumeFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	bento := &neta.Definition{
+		ID:   "resume-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{ID: "first", Type: "counter", Parameters: map[string]interface{}{"value": "a"}},
+			{ID: "second", Type: "flaky", Parameters: map[string]interface{}{"value": "{{.first.value}}"}},
+		},
+		Edges: []neta.Edge{
+			{ID: "edge-1", Source: "first", Target: "second"},
+		},
+	}
+
+	counterCalls, flakyCalls := 0, 0
+	noFail, flakyFails := false, true
+
+	p := pantry.New()
+	p.RegisterFactory("counter", func() neta.Executable {
+		return &countingNeta{calls: &counterCalls, fail: &noFail}
+	})
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &countingNeta{calls: &flakyCalls, fail: &flakyFails}
+	})
+
+	// First run: "second" fails after "first" is checkpointed
+	cp, err := itamae.NewCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("NewCheckpoint failed: %v", err)
+	}
+	chef := itamae.New(p, nil)
+	chef.SetCheckpoint(cp)
+	if _, err := chef.Serve(ctx, bento); err == nil {
+		t.Fatal("Expected first run to fail")
+	}
+
+	// Second run: resume from the checkpoint directory
+	flakyFails = false
+	resumed, err := itamae.LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if resumed.NodeCount() != 1 {
+		t.Errorf("NodeCount = %d, want 1", resumed.NodeCount())
+	}
+
+	chef = itamae.New(p, nil)
+	chef.SetCheckpoint(resumed)
+	result, err := chef.Serve(ctx, bento)
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+
+	if counterCalls != 1 {
+		t.Errorf("first executed %d times, want 1 (should be restored)", counterCalls)
+	}
+	if result.NodesRestored != 1 || result.NodesExecuted != 1 {
+		t.Errorf("NodesRestored = %d, NodesExecuted = %d, want 1 and 1", result.NodesRestored, result.NodesExecuted)
+	}
+
+	output, ok := result.NodeOutputs["second"].(map[string]interface{})
+	if !ok || output["value"] != "a" {
+		t.Errorf("second output = %v, want restored value to flow downstream", result.NodeOutputs["second"])
+	}
+}
+
+// TestItamae_ResumeLoopIterations tests that finished forEach iterations are skipped on resume.
+func TestItamae_ResumeLoopIterations(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	bento := &neta.Definition{
+		ID:   "resume-loop-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{
+				ID:   "loop",
+				Type: "loop",
+				Name: "Loop",
+				Parameters: map[string]interface{}{
+					"mode":  "forEach",
+					"items": []interface{}{"a", "b", "c"},
+				},
+				Nodes: []neta.Definition{
+					{ID: "child", Type: "flaky", Parameters: map[string]interface{}{"value": "{{.item}}"}},
+				},
+			},
+		},
+	}
+
+	calls := 0
+	failOn := "c"
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &itemFailingNeta{calls: &calls, failOn: &failOn}
+	})
+
+	cp, err := itamae.NewCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("NewCheckpoint failed: %v", err)
+	}
+	chef := itamae.New(p, nil)
+	chef.SetCheckpoint(cp)
+	if _, err := chef.Serve(ctx, bento); err == nil {
+		t.Fatal("Expected first run to fail on item c")
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+
+	failOn = ""
+	resumed, err := itamae.LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	chef = itamae.New(p, nil)
+	chef.SetCheckpoint(resumed)
+	result, err := chef.Serve(ctx, bento)
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4 (only item c re-executed)", calls)
+	}
+	loopOutput, ok := result.NodeOutputs["loop"].([]interface{})
+	if !ok || len(loopOutput) != 3 {
+		t.Fatalf("loop output = %v, want 3 iterations", result.NodeOutputs["loop"])
+	}
+}
+
+// TestItamae_ResumeChangedBento tests that checkpointed outputs of edited
+// nodes and of changed loop items are not restored.
+func TestItamae_ResumeChangedBento(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	loop := func(items ...interface{}) *neta.Definition {
+		return &neta.Definition{
+			ID:   "resume-loop-bento",
+			Type: "group",
+			Nodes: []neta.Definition{
+				{ID: "title", Type: "flaky", Parameters: map[string]interface{}{"value": "Renders"}},
+				{
+					ID:         "loop",
+					Type:       "loop",
+					Parameters: map[string]interface{}{"mode": "forEach", "items": items},
+					Nodes: []neta.Definition{
+						{ID: "child", Type: "flaky", Parameters: map[string]interface{}{"value": "{{.item}}"}},
+					},
+				},
+			},
+			Edges: []neta.Edge{{ID: "edge-1", Source: "title", Target: "loop"}},
+		}
+	}
+
+	calls := 0
+	failOn := "c"
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &itemFailingNeta{calls: &calls, failOn: &failOn}
+	})
+
+	cp, err := itamae.NewCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("NewCheckpoint failed: %v", err)
+	}
+	chef := itamae.New(p, nil)
+	chef.SetCheckpoint(cp)
+	if _, err := chef.Serve(ctx, loop("a", "b", "c")); err == nil {
+		t.Fatal("Expected first run to fail on item c")
+	}
+
+	// Items a and b trade places and the title changes
+	failOn = ""
+	calls = 0
+	changed := loop("b", "a", "c")
+	changed.Nodes[0].Parameters["value"] = "Products"
+
+	resumed, err := itamae.LoadCheckpoint(dir)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	chef = itamae.New(p, nil)
+	chef.SetCheckpoint(resumed)
+	result, err := chef.Serve(ctx, changed)
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+
+	if calls != 4 || result.NodesRestored != 0 {
+		t.Errorf("calls = %d, NodesRestored = %d, want 4 and 0 (nothing restored)", calls, result.NodesRestored)
+	}
+	iterations := result.NodeOutputs["loop"].([]interface{})
+	first := iterations[0].(map[string]interface{})["child"].(map[string]interface{})
+	if first["value"] != "b" {
+		t.Errorf("first iteration value = %v, want b", first["value"])
+	}
+}
+
+// itemFailingNeta fails when its value matches failOn.
+type itemFailingNeta struct {
+	calls  *int
+	failOn *string
+}
+
+func (n *itemFailingNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	*n.calls++
+	if params["value"] == *n.failOn {
+		return nil, fmt.Errorf("failed on %v", params["value"])
+	}
+	return map[string]interface{}{"value": params["value"]}, nil
+}
@@ -0,0 +1,114 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// sleepyNeta waits for "delay" or cancellation, then optionally fails.
+type sleepyNeta struct {
+	cancelled chan<- struct{}
+}
+
+func (s *sleepyNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	delay, _ := time.ParseDuration(fmt.Sprint(params["delay"]))
+	select {
+	case <-time.After(delay):
+		if params["fail"] == true {
+			return nil, fmt.Errorf("texture download failed")
+		}
+		return map[string]interface{}{"value": params["value"]}, nil
+	case <-ctx.Done():
+		s.cancelled <- struct{}{}
+		return nil, ctx.Err()
+	}
+}
+
+// newSleepyPantry creates a pantry with the sleepy neta registered.
+func newSleepyPantry(cancelled chan<- struct{}) *pantry.Pantry {
+	p := pantry.New()
+	p.RegisterFactory("sleepy", func() neta.Executable { return &sleepyNeta{cancelled: cancelled} })
+	return p
+}
+
+// TestItamae_GroupConcurrentBranches tests that independent branches overlap.
+func TestItamae_GroupConcurrentBranches(t *testing.T) {
+	bento := &neta.Definition{
+		ID:         "concurrent-bento",
+		Type:       "group",
+		Parameters: map[string]interface{}{"maxConcurrency": float64(2)},
+		Nodes: []neta.Definition{
+			{ID: "textures", Type: "sleepy", Parameters: map[string]interface{}{"delay": "150ms", "value": "tex"}},
+			{ID: "csv", Type: "sleepy", Parameters: map[string]interface{}{"delay": "150ms", "value": "rows"}},
+			{ID: "join", Type: "sleepy", Parameters: map[string]interface{}{
+				"delay": "0s",
+				"value": "{{.textures.value}}+{{.csv.value}}",
+			}},
+		},
+		Edges: []neta.Edge{
+			{ID: "e1", Source: "textures", Target: "join"},
+			{ID: "e2", Source: "csv", Target: "join"},
+		},
+	}
+
+	cancelled := make(chan struct{}, 2)
+	start := time.Now()
+	result, err := itamae.New(newSleepyPantry(cancelled), nil).Serve(context.Background(), bento)
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 280*time.Millisecond {
+		t.Errorf("Serve took %v, branches did not run concurrently", elapsed)
+	}
+	if result.NodesExecuted != 3 {
+		t.Errorf("NodesExecuted = %d, want 3", result.NodesExecuted)
+	}
+
+	join := result.NodeOutputs["join"].(map[string]interface{})
+	if join["value"] != "tex+rows" {
+		t.Errorf("join value = %v, want %q", join["value"], "tex+rows")
+	}
+}
+
+// TestItamae_GroupConcurrentFailureCancels tests that the first failure cancels siblings.
+func TestItamae_GroupConcurrentFailureCancels(t *testing.T) {
+	bento := &neta.Definition{
+		ID:         "failing-bento",
+		Type:       "group",
+		Parameters: map[string]interface{}{"maxConcurrency": float64(4)},
+		Nodes: []neta.Definition{
+			{ID: "slow", Type: "sleepy", Parameters: map[string]interface{}{"delay": "5s"}},
+			{ID: "broken", Type: "sleepy", Parameters: map[string]interface{}{"delay": "50ms", "fail": true}},
+			{ID: "after", Type: "sleepy", Parameters: map[string]interface{}{"delay": "0s"}},
+		},
+		Edges: []neta.Edge{
+			{ID: "e1", Source: "broken", Target: "after"},
+		},
+	}
+
+	cancelled := make(chan struct{}, 2)
+	start := time.Now()
+	result, err := itamae.New(newSleepyPantry(cancelled), nil).Serve(context.Background(), bento)
+	if err == nil {
+		t.Fatal("Expected error from failing branch")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Serve took %v, sibling was not cancelled", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("In-flight sibling did not observe cancellation")
+	}
+	if _, ok := result.NodeOutputs["after"]; ok {
+		t.Error("Downstream node should not run after a failure")
+	}
+}
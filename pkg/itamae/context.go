@@ -2,32 +2,141 @@ package itamae
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/Develonaut/bento/pkg/wasabi"
 )
 
 // executionContext holds data passed between nodes during execution.
+//
+// Node data lives in copy-on-write layers: a copy shares all of its parent's
+// data and only gets a layer of its own when it is written to, so creating
+// one is O(1) however much data the run has accumulated. The environment is
+// read and the secrets manager opened once per run.
 type executionContext struct {
-	nodeData       map[string]interface{} // Data from each executed node
+	nodeData       *layeredData           // Data from the environment and each executed node
+	env            *scope                 // Environment layer at the bottom of every run
 	secretsManager *wasabi.Manager        // Secrets manager for {{SECRETS.X}} resolution
 	depth          int                    // Nesting depth for logging indentation
 	path           []string               // Breadcrumb path of node names
+	bentoDir       string                 // Directory relative sub-bento paths and file patterns resolve against
+	bentoDepth     int                    // Number of enclosing sub-bento calls
+	inputs         map[string]interface{} // Named inputs of the current node (from port edges)
+	variables      map[string]bool        // Names of the bento's variables (kept out of traces)
+	strict         bool                   // Unresolvable templates are errors (see resolveParam)
+}
+
+// layeredData is the node data of one context: frozen layers shared with
+// other contexts plus the context's own writes.
+type layeredData struct {
+	mu    sync.Mutex
+	base  *scope                 // Frozen layers, shared with other contexts
+	local map[string]interface{} // Writes since the last copy (nil until the first write)
+	view  map[string]interface{} // Cached flattened data (nil after a write)
+}
+
+// scope is one frozen layer of node data over its parent's.
+// Scopes are never written once created, so contexts on different
+// goroutines can share them.
+type scope struct {
+	parent *scope
+	data   map[string]interface{}
+
+	once sync.Once
+	flat map[string]interface{} // All data visible through this layer (built on first use)
+}
+
+// get looks a key up in this layer, then its parents.
+func (s *scope) get(key string) (interface{}, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.data[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// flatten returns all data visible through this layer. The result is shared
+// and must not be modified.
+func (s *scope) flatten() map[string]interface{} {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		s.flat = maps.Clone(s.parent.flatten())
+		if s.flat == nil {
+			s.flat = make(map[string]interface{}, len(s.data))
+		}
+		maps.Copy(s.flat, s.data)
+	})
+	return s.flat
+}
+
+// set stores a value in the local layer.
+func (d *layeredData) set(key string, value interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.local == nil {
+		d.local = make(map[string]interface{})
+	}
+	d.local[key] = value
+	d.view = nil
+}
+
+// get looks a key up in the local layer, then the frozen ones.
+func (d *layeredData) get(key string) (interface{}, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if v, ok := d.local[key]; ok {
+		return v, true
+	}
+	return d.base.get(key)
+}
+
+// fork freezes pending writes into a layer and returns new data sharing
+// all layers. Later writes to either are not visible to the other.
+func (d *layeredData) fork() *layeredData {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.local) > 0 {
+		d.base = &scope{parent: d.base, data: d.local}
+		d.local = nil
+		d.view = nil
+	}
+	return &layeredData{base: d.base}
+}
+
+// flatten returns all visible data in one map, cached until the next write.
+// The result is shared and must not be modified.
+func (d *layeredData) flatten() map[string]interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.view == nil {
+		if len(d.local) == 0 {
+			d.view = d.base.flatten()
+		} else {
+			d.view = maps.Clone(d.base.flatten())
+			maps.Copy(d.view, d.local)
+		}
+	}
+	return d.view
 }
 
 // newExecutionContext creates a new execution context.
-// Initializes nodeData with environment variables so templates can access them.
+// Initializes the bottom layer with environment variables so templates can access them.
 func newExecutionContext() *executionContext {
-	nodeData := make(map[string]interface{})
+	env := make(map[string]interface{})
 
 	// Load all environment variables into context
 	// This allows templates like {{.FIGMA_API_URL}} to work
-	for _, env := range os.Environ() {
+	for _, kv := range os.Environ() {
 		// Split on first '=' to handle values that contain '='
-		parts := strings.SplitN(env, "=", 2)
+		parts := strings.SplitN(kv, "=", 2)
 		if len(parts) == 2 {
-			nodeData[parts[0]] = parts[1]
+			env[parts[0]] = parts[1]
 		}
 	}
 
@@ -40,8 +149,10 @@ func newExecutionContext() *executionContext {
 		secretsMgr = nil
 	}
 
+	envScope := &scope{data: env}
 	return &executionContext{
-		nodeData:       nodeData,
+		nodeData:       &layeredData{base: envScope},
+		env:            envScope,
 		secretsManager: secretsMgr,
 		depth:          0,
 		path:           []string{},
@@ -50,26 +161,60 @@ func newExecutionContext() *executionContext {
 
 // set stores output from a node.
 func (ec *executionContext) set(nodeID string, data interface{}) {
-	ec.nodeData[nodeID] = data
+	ec.nodeData.set(nodeID, data)
+}
+
+// get returns the data stored under key (a node ID or variable name).
+func (ec *executionContext) get(key string) (interface{}, bool) {
+	return ec.nodeData.get(key)
 }
 
-// copy creates a shallow copy of the execution context.
-// Note: This performs a shallow copy - the nodeData map is copied,
-// but the values within the map are not deep-copied. This is intentional
-// for performance and works correctly because node outputs are immutable
-// after being set. The secrets manager is shared across copies.
+// copy creates a copy of the execution context in O(1).
+// The copy shares the data stored so far; later writes to either context
+// are not visible to the other. Values are not deep-copied, which works
+// because node outputs are immutable after being set. The secrets manager
+// is shared across copies.
 func (ec *executionContext) copy() *executionContext {
-	newCtx := newExecutionContext()
-	for k, v := range ec.nodeData {
-		newCtx.nodeData[k] = v
+	return &executionContext{
+		nodeData:       ec.nodeData.fork(),
+		env:            ec.env,
+		secretsManager: ec.secretsManager,
+		depth:          ec.depth,
+		path:           append([]string{}, ec.path...),
+		bentoDir:       ec.bentoDir,
+		bentoDepth:     ec.bentoDepth,
+		variables:      ec.variables,
+		strict:         ec.strict,
+	}
+}
+
+// setVariables stores the bento's variables, remembering their names.
+func (ec *executionContext) setVariables(vars map[string]interface{}) {
+	ec.variables = make(map[string]bool, len(vars))
+	for name, value := range vars {
+		ec.set(name, value)
+		ec.variables[name] = true
+	}
+}
+
+// withoutVariables returns a strict context holding only node data: no
+// environment, variables or secrets, so templates referencing them can't
+// be resolved (used to trace parameters without credentials).
+func (ec *executionContext) withoutVariables() *executionContext {
+	data := make(map[string]interface{})
+	for key, value := range ec.nodeData.flatten() {
+		if _, isEnv := ec.env.get(key); isEnv || ec.variables[key] {
+			continue
+		}
+		data[key] = value
+	}
+	return &executionContext{
+		nodeData: &layeredData{base: &scope{data: data}},
+		env:      &scope{},
+		path:     ec.path,
+		inputs:   ec.inputs,
+		strict:   true,
 	}
-	// Share the same secrets manager (thread-safe)
-	newCtx.secretsManager = ec.secretsManager
-	// Preserve depth and path
-	newCtx.depth = ec.depth
-	newCtx.path = make([]string, len(ec.path))
-	copy(newCtx.path, ec.path)
-	return newCtx
 }
 
 // withNode returns a copy of the context with a node added to the path.
@@ -80,6 +225,27 @@ func (ec *executionContext) withNode(nodeName string) *executionContext {
 	return newCtx
 }
 
+// withEnv returns an empty context that shares only the run's environment
+// layer and secrets manager (a sub-bento doesn't see its caller's data).
+func (ec *executionContext) withEnv() *executionContext {
+	return &executionContext{
+		nodeData:       &layeredData{base: ec.env},
+		env:            ec.env,
+		secretsManager: ec.secretsManager,
+		path:           []string{},
+		strict:         ec.strict,
+	}
+}
+
+// withInputs returns the context with named inputs attached for one node.
+// The copy shares nodeData with ec, so the node's output is stored as usual.
+// Inputs are not inherited by copies (child nodes get their own).
+func (ec *executionContext) withInputs(inputs map[string]interface{}) *executionContext {
+	newCtx := *ec
+	newCtx.inputs = inputs
+	return &newCtx
+}
+
 // getBreadcrumb returns the breadcrumb path as a formatted string.
 // Format: "Node1:Node2:Node3" (no brackets)
 func (ec *executionContext) getBreadcrumb() string {
@@ -91,14 +257,10 @@ func (ec *executionContext) getBreadcrumb() string {
 
 // toMap converts the context to a map for external use.
 func (ec *executionContext) toMap() map[string]interface{} {
-	result := make(map[string]interface{})
-	for k, v := range ec.nodeData {
-		result[k] = v
-	}
-	return result
+	return maps.Clone(ec.nodeData.flatten())
 }
 
 // String returns a string representation for debugging.
 func (ec *executionContext) String() string {
-	return fmt.Sprintf("executionContext{nodes: %d}", len(ec.nodeData))
+	return fmt.Sprintf("executionContext{nodes: %d}", len(ec.nodeData.flatten()))
 }
@@ -0,0 +1,77 @@
+package itamae
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// largeContext returns a context holding the outputs of n nodes.
+func largeContext(n int) *executionContext {
+	ctx := newExecutionContext()
+	for i := 0; i < n; i++ {
+		ctx.set(fmt.Sprintf("node-%d", i), map[string]interface{}{"index": i, "status": "done"})
+	}
+	return ctx
+}
+
+// BenchmarkExecutionContext_WithNode measures creating an iteration scope.
+func BenchmarkExecutionContext_WithNode(b *testing.B) {
+	ctx := largeContext(1000)
+	for b.Loop() {
+		iterCtx := ctx.withNode("Loop")
+		iterCtx.set("item", "chair")
+		iterCtx.set("index", 1)
+	}
+}
+
+// BenchmarkExecutionContext_ResolveIteration measures creating an iteration
+// scope and resolving a template against it.
+func BenchmarkExecutionContext_ResolveIteration(b *testing.B) {
+	ctx := largeContext(1000)
+	for b.Loop() {
+		iterCtx := ctx.withNode("Loop")
+		iterCtx.set("item", map[string]interface{}{"name": "chair"})
+		iterCtx.set("index", 1)
+		iterCtx.resolveValue("{{.item.name}}_{{.index}}.png")
+	}
+}
+
+// noopNeta returns its input without doing any work.
+type noopNeta struct{}
+
+func (n *noopNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{"value": params["value"]}, nil
+}
+
+// BenchmarkItamae_ForEach5000 measures a forEach loop over 5,000 rows.
+func BenchmarkItamae_ForEach5000(b *testing.B) {
+	items := make([]interface{}, 5000)
+	for i := range items {
+		items[i] = map[string]interface{}{"sku": fmt.Sprintf("SKU-%d", i)}
+	}
+
+	p := pantry.New()
+	p.RegisterFactory("noop", func() neta.Executable { return &noopNeta{} })
+	def := &neta.Definition{
+		ID:   "rows",
+		Type: "loop",
+		Name: "Rows",
+		Parameters: map[string]interface{}{
+			"mode":  "forEach",
+			"items": items,
+		},
+		Nodes: []neta.Definition{
+			{ID: "render", Type: "noop", Name: "Render", Parameters: map[string]interface{}{"value": "{{.item.sku}}"}},
+		},
+	}
+
+	for b.Loop() {
+		if _, err := New(p, nil).Serve(context.Background(), def); err != nil {
+			b.Fatalf("Serve failed: %v", err)
+		}
+	}
+}
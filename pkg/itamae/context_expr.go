@@ -0,0 +1,130 @@
+package itamae
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// containsExpression checks if a string contains an inline expression.
+func containsExpression(s string) bool {
+	return strings.Contains(s, "${{")
+}
+
+// inlineExpression is an inline expression found in a string:
+// ${{ len(item.angles) * 2 }}.
+type inlineExpression struct {
+	start, end int    // Offsets of "${{" and just past the closing "}}"
+	code       string // The expression between them
+}
+
+// findExpressions returns the inline expressions in s. An expression ends at
+// the first "}}" outside its string literals and its own braces, so map
+// literals and closures ({"a": {"b": 1}}) stay whole. An expression that is
+// never closed is left as text.
+func findExpressions(s string) []inlineExpression {
+	var found []inlineExpression
+	for offset := 0; ; {
+		start := strings.Index(s[offset:], "${{")
+		if start < 0 {
+			return found
+		}
+		start += offset
+		end, ok := expressionEnd(s, start+3)
+		if !ok {
+			return found
+		}
+		found = append(found, inlineExpression{start: start, end: end, code: s[start+3 : end-2]})
+		offset = end
+	}
+}
+
+// expressionEnd returns the offset just past the "}}" closing an expression
+// whose code starts at from.
+func expressionEnd(s string, from int) (int, bool) {
+	depth := 0
+	var quote byte // Delimiter of the string literal being scanned
+	for idx := from; idx < len(s); idx++ {
+		c := s[idx]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				idx++ // Skip the escaped character
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == '}' && idx+1 < len(s) && s[idx+1] == '}':
+			return idx + 2, true
+		}
+	}
+	return 0, false
+}
+
+// isExactExpression checks if a string is EXACTLY one inline expression.
+func isExactExpression(s string) bool {
+	trimmed := strings.TrimSpace(s)
+	found := findExpressions(trimmed)
+	return len(found) == 1 && found[0].start == 0 && found[0].end == len(trimmed)
+}
+
+// resolveExpressions evaluates the ${{ }} expressions in a string.
+//
+// A string that is exactly one expression resolves to its typed result
+// (number, bool, list, map). Otherwise each expression is replaced by its
+// result formatted as text, leaving the rest of the string as it is.
+func (ec *executionContext) resolveExpressions(s string) (interface{}, error) {
+	if isExactExpression(s) {
+		return ec.evaluateExpression(findExpressions(strings.TrimSpace(s))[0].code)
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, e := range findExpressions(s) {
+		value, err := ec.evaluateExpression(e.code)
+		if err != nil {
+			return s, err
+		}
+		out.WriteString(s[last:e.start])
+		out.WriteString(fmt.Sprint(value))
+		last = e.end
+	}
+	out.WriteString(s[last:])
+	return out.String(), nil
+}
+
+// evaluateCondition runs an expr-lang condition against the context.
+// Unknown names are nil, so a condition can test for optional values
+// (item.overlay != nil) and for nodes that didn't run.
+func (ec *executionContext) evaluateCondition(code string) (bool, error) {
+	env := ec.nodeData.flatten()
+
+	program, err := expr.Compile(code, expr.Env(env), expr.AllowUndefinedVariables(), expr.AsBool())
+	if err != nil {
+		return false, err
+	}
+	output, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+	return output.(bool), nil
+}
+
+// evaluateExpression runs an expr-lang expression against the context.
+// Node outputs and loop variables are top-level names (item, index);
+// IDs that aren't identifiers are reachable through $env["read-csv"].
+func (ec *executionContext) evaluateExpression(code string) (interface{}, error) {
+	env := ec.nodeData.flatten()
+
+	program, err := expr.Compile(strings.TrimSpace(code), expr.Env(env))
+	if err != nil {
+		return nil, err
+	}
+	return expr.Run(program, env)
+}
@@ -0,0 +1,99 @@
+package itamae
+
+import (
+	"text/template/parse"
+)
+
+// checkReferences verifies that the context references in a parsed template
+// exist (strict mode).
+//
+// A reference printed on its own ({{.node.field}}) must exist in full.
+// References in conditions, pipelines and function arguments, and anything
+// inside an if, only need their root (a node ID, variable or environment
+// variable), so optional fields still work:
+// {{if .item.color}}{{.item.color}}{{else}}red{{end}}. Bodies of range and
+// with are not checked, as dot refers to something else there.
+func (ec *executionContext) checkReferences(root *parse.ListNode) error {
+	return ec.checkList(root, false)
+}
+
+// checkList checks the nodes of a list. guarded is set inside conditionals.
+func (ec *executionContext) checkList(list *parse.ListNode, guarded bool) error {
+	if list == nil {
+		return nil
+	}
+	for _, node := range list.Nodes {
+		if err := ec.checkNode(node, guarded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNode checks the references in one template node.
+func (ec *executionContext) checkNode(node parse.Node, guarded bool) error {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		return ec.checkPipe(n.Pipe, !guarded && len(n.Pipe.Decl) == 0)
+	case *parse.IfNode:
+		if err := ec.checkPipe(n.Pipe, false); err != nil {
+			return err
+		}
+		if err := ec.checkList(n.List, true); err != nil {
+			return err
+		}
+		return ec.checkList(n.ElseList, true)
+	case *parse.RangeNode:
+		return ec.checkPipe(n.Pipe, false)
+	case *parse.WithNode:
+		if err := ec.checkPipe(n.Pipe, false); err != nil {
+			return err
+		}
+		return ec.checkList(n.ElseList, true)
+	}
+	return nil
+}
+
+// checkPipe checks the references in a pipeline. printed reports whether
+// the pipeline's value is output directly.
+func (ec *executionContext) checkPipe(pipe *parse.PipeNode, printed bool) error {
+	if pipe == nil {
+		return nil
+	}
+	full := printed && len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if err := ec.checkArg(arg, full); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkArg checks one operand: the full key path when full is set,
+// otherwise just its root.
+func (ec *executionContext) checkArg(arg parse.Node, full bool) error {
+	var keys []string
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		keys = n.Ident
+	case *parse.VariableNode:
+		if n.Ident[0] != "$" || len(n.Ident) < 2 {
+			return nil
+		}
+		keys = n.Ident[1:]
+	case *parse.PipeNode:
+		return ec.checkPipe(n, false)
+	case *parse.ChainNode:
+		return ec.checkArg(n.Node, false)
+	default:
+		return nil
+	}
+
+	if !full {
+		keys = keys[:1]
+	}
+	_, err := ec.lookupPath(keys)
+	return err
+}
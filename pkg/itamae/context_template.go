@@ -4,94 +4,187 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/Develonaut/bento/pkg/furikake"
 	"github.com/Develonaut/bento/pkg/kombu"
 )
 
 // resolveValue recursively resolves template strings in a value.
+// Templates that can't be resolved are left as they are, even in strict mode.
 func (ec *executionContext) resolveValue(value interface{}) interface{} {
+	resolved, err := ec.resolveParam("", value)
+	if err != nil {
+		return value
+	}
+	return resolved
+}
+
+// resolveParams resolves templates in a node's parameters.
+func (ec *executionContext) resolveParams(params map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		value, err := ec.resolveParam(k, v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[k] = value
+	}
+	return resolved, nil
+}
+
+// resolveParam recursively resolves template strings in the parameter at path.
+// In strict mode a template that can't be resolved is an error naming the
+// parameter path and the template; otherwise it is left as it is.
+func (ec *executionContext) resolveParam(path string, value interface{}) (interface{}, error) {
 	switch v := value.(type) {
 	case string:
-		return ec.resolveString(v)
+		return ec.resolveString(path, v)
 	case map[string]interface{}:
-		return ec.resolveMap(v)
+		return ec.resolveMap(path, v)
 	case []interface{}:
-		return ec.resolveSlice(v)
+		return ec.resolveSlice(path, v)
 	default:
-		return value
+		return value, nil
 	}
 }
 
+// templateError is a parameter template that couldn't be resolved.
+type templateError struct {
+	path  string // Parameter path, e.g. "values.token" or "args[2]"
+	expr  string // The template as written (secrets unresolved)
+	cause error
+}
+
+// Error returns the error message.
+func (e *templateError) Error() string {
+	if e.path == "" {
+		return fmt.Sprintf("cannot resolve %q: %v", e.expr, e.cause)
+	}
+	return fmt.Sprintf("parameter '%s': cannot resolve %q: %v", e.path, e.expr, e.cause)
+}
+
+// Unwrap returns the underlying error.
+func (e *templateError) Unwrap() error {
+	return e.cause
+}
+
 // resolveSecretsInString resolves {{SECRETS.X}} placeholders from keychain.
-// Returns the string with secrets resolved, or original string if error occurs.
-func (ec *executionContext) resolveSecretsInString(s string) string {
-	if ec.secretsManager == nil || !strings.Contains(s, "{{SECRETS.") {
-		return s
+// Returns the original string and an error if resolution fails.
+func (ec *executionContext) resolveSecretsInString(s string) (string, error) {
+	if !strings.Contains(s, "{{SECRETS.") {
+		return s, nil
+	}
+	if ec.secretsManager == nil {
+		return s, fmt.Errorf("secrets manager unavailable")
 	}
 
 	resolved, err := ec.secretsManager.ResolveTemplate(s)
 	if err != nil {
-		// SECRET RESOLUTION FAILED - This is a CRITICAL error
-		fmt.Fprintf(os.Stderr, "\n❌ ERROR: Failed to resolve secrets in template: %v\n", err)
-		fmt.Fprintf(os.Stderr, "   Template: %s\n", s)
-		fmt.Fprintf(os.Stderr, "   This will likely cause authentication failures!\n\n")
-		return s
+		return s, err
 	}
-	return resolved
+	return resolved, nil
+}
+
+// warnSecretFailure reports a failed secret resolution in non-strict mode.
+func warnSecretFailure(s string, err error) {
+	// SECRET RESOLUTION FAILED - This is a CRITICAL error
+	fmt.Fprintf(os.Stderr, "\n❌ ERROR: Failed to resolve secrets in template: %v\n", err)
+	fmt.Fprintf(os.Stderr, "   Template: %s\n", s)
+	fmt.Fprintf(os.Stderr, "   This will likely cause authentication failures!\n\n")
 }
 
 // executeGoTemplate parses and executes a Go template string.
-// Returns the interpolated string, or the input if parsing/execution fails.
-func (ec *executionContext) executeGoTemplate(s string) string {
+// Returns the interpolated string, or the input and an error if
+// parsing/execution fails. Missing references are errors in strict mode.
+func (ec *executionContext) executeGoTemplate(s string) (string, error) {
 	tmpl, err := template.New("param").Funcs(templateFuncs()).Parse(s)
 	if err != nil {
-		return s
+		return s, err
+	}
+	if ec.strict && tmpl.Tree != nil {
+		if err := ec.checkReferences(tmpl.Tree.Root); err != nil {
+			return s, err
+		}
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, ec.nodeData); err != nil {
-		return s
+	if err := tmpl.Execute(&buf, ec.nodeData.flatten()); err != nil {
+		return s, err
 	}
 
-	return buf.String()
+	return buf.String(), nil
 }
 
 // resolveString resolves template syntax in a string.
 // Resolution order:
 //  1. {{SECRETS.X}} - Keychain secrets
-//  2. {{BENTO_HOME}}, {{GDRIVE}}, etc. - Special path markers
-//  3. {{.X}} - Go template variables from context
+//  2. ${{ expr }} - expr-lang expressions against the context
+//  3. {{BENTO_HOME}}, {{GDRIVE}}, etc. - Special path markers
+//  4. {{.X}} - Go template variables from context
+//
+// If the string is ONLY a template or expression (no literal text), return
+// the actual value. Otherwise, return the string interpolation.
 //
-// If the string is ONLY a template (no literal text), return the actual value.
-// Otherwise, return the string interpolation.
-func (ec *executionContext) resolveString(s string) interface{} {
+// Errors are only returned in strict mode; otherwise unresolvable templates
+// are returned as they are.
+func (ec *executionContext) resolveString(path, s string) (interface{}, error) {
 	// Step 1: Resolve {{SECRETS.X}} placeholders from keychain
-	resolvedSecrets := ec.resolveSecretsInString(s)
+	resolvedSecrets, err := ec.resolveSecretsInString(s)
+	if err != nil {
+		if ec.strict {
+			return nil, &templateError{path: path, expr: s, cause: err}
+		}
+		warnSecretFailure(s, err)
+	}
 
-	// Step 2: Resolve special path markers ({{BENTO_HOME}}, {{GDRIVE}}, etc.)
+	// Step 2: Evaluate inline expressions (${{ expr }}) before environment
+	// variables are expanded
+	if containsExpression(resolvedSecrets) {
+		val, err := ec.resolveExpressions(resolvedSecrets)
+		if err != nil {
+			if ec.strict {
+				return nil, &templateError{path: path, expr: s, cause: err}
+			}
+			return resolvedSecrets, nil
+		}
+		str, ok := val.(string)
+		if !ok {
+			return val, nil
+		}
+		resolvedSecrets = str
+	}
+
+	// Step 3: Resolve special path markers ({{BENTO_HOME}}, {{GDRIVE}}, etc.)
 	resolvedPaths, err := kombu.ResolvePath(resolvedSecrets)
 	if err != nil {
 		// If path resolution fails, continue with original string
 		resolvedPaths = resolvedSecrets
 	}
 
-	// Step 3: Check if string contains Go template syntax ({{.X}})
+	// Step 4: Check if string contains Go template syntax ({{.X}})
 	if !containsTemplate(resolvedPaths) {
-		return resolvedPaths
+		return resolvedPaths, nil
 	}
 
-	// Step 4: Special case - if entire string is single template, return actual value
+	// Step 5: Special case - if entire string is single template, return actual value
 	if isExactTemplate(resolvedPaths) {
-		if val := ec.resolveExactTemplate(resolvedPaths); val != nil {
-			return val
+		val, err := ec.resolveExactTemplate(resolvedPaths)
+		if val != nil {
+			return val, nil
+		}
+		if err != nil && ec.strict {
+			return nil, &templateError{path: path, expr: s, cause: err}
 		}
 	}
 
-	// Step 5: Parse and execute Go template (returns string interpolation)
-	return ec.executeGoTemplate(resolvedPaths)
+	// Step 6: Parse and execute Go template (returns string interpolation)
+	out, err := ec.executeGoTemplate(resolvedPaths)
+	if err != nil && ec.strict {
+		return nil, &templateError{path: path, expr: s, cause: err}
+	}
+	return out, nil
 }
 
 // isExactTemplate checks if a string is EXACTLY one template (no literal text).
@@ -100,12 +193,18 @@ func isExactTemplate(s string) bool {
 	return strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}")
 }
 
-// resolveExactTemplate resolves a template that is exactly one expression.
-// Returns the actual value from context (array, map, etc.) instead of string.
-func (ec *executionContext) resolveExactTemplate(s string) interface{} {
+// resolveExactTemplate resolves a template that is exactly one reference
+// ({{.key.subkey}} or {{index . "key"}}).
+// Returns the actual value from context (array, map, etc.) instead of string,
+// or an error if the reference doesn't exist. Returns nil and no error for
+// other expressions (pipelines, function calls).
+func (ec *executionContext) resolveExactTemplate(s string) (interface{}, error) {
 	// Extract the expression between {{ and }}
 	trimmed := strings.TrimSpace(s)
 	expr := strings.TrimSpace(trimmed[2 : len(trimmed)-2])
+	if strings.ContainsAny(expr, "{}|()") {
+		return nil, nil // Several templates or a pipeline
+	}
 
 	// Handle "index . \"key1\" \"key2\"..." syntax
 	if strings.HasPrefix(expr, "index .") {
@@ -113,19 +212,19 @@ func (ec *executionContext) resolveExactTemplate(s string) interface{} {
 	}
 
 	// Handle simple ".key" or ".key.subkey" syntax
-	if strings.HasPrefix(expr, ".") {
+	if strings.HasPrefix(expr, ".") && !strings.ContainsAny(expr, " \t") {
 		return ec.resolveDotExpression(expr[1:]) // Remove leading dot
 	}
 
-	return nil
+	return nil, nil
 }
 
 // resolveIndexExpression resolves {{index . "key1" "key2"}} expressions.
-func (ec *executionContext) resolveIndexExpression(expr string) interface{} {
+func (ec *executionContext) resolveIndexExpression(expr string) (interface{}, error) {
 	// Parse: index . "key1" "key2" ...
 	parts := strings.Fields(expr)
 	if len(parts) < 3 || parts[0] != "index" || parts[1] != "." {
-		return nil
+		return nil, nil
 	}
 
 	// Extract keys (remove quotes)
@@ -135,51 +234,63 @@ func (ec *executionContext) resolveIndexExpression(expr string) interface{} {
 		keys = append(keys, key)
 	}
 
-	// Navigate through the context
-	var current interface{} = ec.nodeData
-	for _, key := range keys {
-		m, ok := current.(map[string]interface{})
-		if !ok {
-			return nil
-		}
-		current = m[key]
-	}
-
-	return current
+	return ec.lookupPath(keys)
 }
 
 // resolveDotExpression resolves {{.key.subkey}} expressions.
-func (ec *executionContext) resolveDotExpression(expr string) interface{} {
-	keys := strings.Split(expr, ".")
-	var current interface{} = ec.nodeData
+func (ec *executionContext) resolveDotExpression(expr string) (interface{}, error) {
+	return ec.lookupPath(strings.Split(expr, "."))
+}
 
-	for _, key := range keys {
+// lookupPath returns the value at a key path in the context.
+// Returns an error naming the first key that doesn't exist, or nil and no
+// error if the path leads through a value that isn't a map.
+func (ec *executionContext) lookupPath(keys []string) (interface{}, error) {
+	current, ok := ec.get(keys[0])
+	if !ok {
+		return nil, fmt.Errorf("no value for '%s'", keys[0])
+	}
+	for idx, key := range keys[1:] {
 		m, ok := current.(map[string]interface{})
 		if !ok {
-			return nil
+			return nil, nil
+		}
+		if current, ok = m[key]; !ok {
+			return nil, fmt.Errorf("'%s' has no key '%s'", strings.Join(keys[:idx+1], "."), key)
 		}
-		current = m[key]
 	}
 
-	return current
+	return current, nil
 }
 
 // resolveMap resolves templates in a map.
-func (ec *executionContext) resolveMap(m map[string]interface{}) map[string]interface{} {
+func (ec *executionContext) resolveMap(path string, m map[string]interface{}) (map[string]interface{}, error) {
 	resolved := make(map[string]interface{})
 	for k, v := range m {
-		resolved[k] = ec.resolveValue(v)
+		childPath := k
+		if path != "" {
+			childPath = path + "." + k
+		}
+		value, err := ec.resolveParam(childPath, v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[k] = value
 	}
-	return resolved
+	return resolved, nil
 }
 
 // resolveSlice resolves templates in a slice.
-func (ec *executionContext) resolveSlice(s []interface{}) []interface{} {
+func (ec *executionContext) resolveSlice(path string, s []interface{}) ([]interface{}, error) {
 	resolved := make([]interface{}, len(s))
 	for i, v := range s {
-		resolved[i] = ec.resolveValue(v)
+		value, err := ec.resolveParam(fmt.Sprintf("%s[%d]", path, i), v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = value
 	}
-	return resolved
+	return resolved, nil
 }
 
 // containsTemplate checks if a string contains template syntax.
@@ -187,9 +298,7 @@ func containsTemplate(s string) bool {
 	return len(s) > 4 && strings.Contains(s, "{{") && strings.Contains(s, "}}")
 }
 
-// templateFuncs returns custom template functions.
+// templateFuncs returns the template functions available to parameters.
 func templateFuncs() template.FuncMap {
-	return template.FuncMap{
-		"basename": filepath.Base,
-	}
+	return furikake.Funcs()
 }
@@ -1,6 +1,9 @@
 package itamae
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -52,15 +55,15 @@ func TestResolveStringWithBasename(t *testing.T) {
 
 			// Add environment variables to context
 			for k, v := range tt.envVars {
-				ctx.nodeData[k] = v
+				ctx.set(k, v)
 			}
 
 			// Resolve the template
-			result := ctx.resolveString(tt.template)
+			result := ctx.resolveValue(tt.template)
 
 			// Check result
 			if result != tt.want {
-				t.Errorf("resolveString(%q) = %q, want %q", tt.template, result, tt.want)
+				t.Errorf("resolveValue(%q) = %q, want %q", tt.template, result, tt.want)
 			}
 		})
 	}
@@ -69,13 +72,170 @@ func TestResolveStringWithBasename(t *testing.T) {
 // TestTemplateWithBasenameInArgs tests basename in command args.
 func TestTemplateWithBasenameInArgs(t *testing.T) {
 	ctx := newExecutionContext()
-	ctx.nodeData["PRODUCT_PATH"] = "/Users/Ryan/Products/Combat Dog (Supplies)"
+	ctx.set("PRODUCT_PATH", "/Users/Ryan/Products/Combat Dog (Supplies)")
 
 	template := "--filename-prefix {{basename .PRODUCT_PATH}}"
-	result := ctx.resolveString(template)
+	result := ctx.resolveValue(template)
 	want := "--filename-prefix Combat Dog (Supplies)"
 
 	if result != want {
-		t.Errorf("resolveString(%q) = %q, want %q", template, result, want)
+		t.Errorf("resolveValue(%q) = %q, want %q", template, result, want)
+	}
+}
+
+// TestExecutionContext_CopyOnWrite tests that copies share data but not later writes.
+func TestExecutionContext_CopyOnWrite(t *testing.T) {
+	parent := newExecutionContext()
+	parent.set("read", "rows")
+
+	child := parent.withNode("Loop")
+	child.set("item", "chair")
+	parent.set("after", "copy")
+
+	if v, _ := child.get("read"); v != "rows" {
+		t.Errorf("child should see data set before the copy, got %v", v)
+	}
+	if _, ok := child.get("after"); ok {
+		t.Error("child should not see data set after the copy")
+	}
+	if _, ok := parent.get("item"); ok {
+		t.Error("parent should not see data set in the child")
+	}
+
+	sub := child.withEnv()
+	if _, ok := sub.get("read"); ok {
+		t.Error("withEnv context should not see node outputs")
+	}
+	if got := child.resolveValue("{{.read}}-{{.item}}"); got != "rows-chair" {
+		t.Errorf("resolveValue = %v, want rows-chair", got)
+	}
+}
+
+// TestResolveParams_Strict tests strict and lenient resolution of unresolvable templates.
+func TestResolveParams_Strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    interface{} // Lenient (and strict, when wantErr is empty)
+		wantErr string      // Expected in the strict error
+	}{
+		{
+			name:    "misspelled node ID",
+			value:   "{{.read-csv.rows}}",
+			want:    "{{.read-csv.rows}}",
+			wantErr: `parameter 'items': cannot resolve "{{.read-csv.rows}}": no value for 'read-csv'`,
+		},
+		{
+			name:    "missing field printed",
+			value:   "{{.item.nme}}.png",
+			want:    "<no value>.png",
+			wantErr: "'item' has no key 'nme'",
+		},
+		{
+			name:    "parse error",
+			value:   "{{if .item.name}}chair",
+			want:    "{{if .item.name}}chair",
+			wantErr: "unexpected EOF",
+		},
+		{
+			name:    "nested parameter path",
+			value:   map[string]interface{}{"args": []interface{}{"-o", "{{.output}}"}},
+			want:    map[string]interface{}{"args": []interface{}{"-o", "<no value>"}},
+			wantErr: "parameter 'items.args[1]'",
+		},
+		{
+			name:  "optional field in condition",
+			value: "{{if .item.color}}{{.item.color}}{{else}}red{{end}}",
+			want:  "red",
+		},
+		{
+			name:  "existing reference",
+			value: "{{.item.name}}",
+			want:  "chair",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newExecutionContext()
+			ctx.set("item", map[string]interface{}{"name": "chair"})
+
+			ctx.strict = false
+			got, err := ctx.resolveParam("items", tt.value)
+			if err != nil || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("lenient resolveParam = %v, %v; want %v", got, err, tt.want)
+			}
+
+			ctx.strict = true
+			got, err = ctx.resolveParam("items", tt.value)
+			if tt.wantErr == "" {
+				if err != nil || fmt.Sprint(got) != fmt.Sprint(tt.want) {
+					t.Errorf("strict resolveParam = %v, %v; want %v", got, err, tt.want)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("strict resolveParam error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestResolveValue_Expressions verifies ${{ }} expressions resolve to typed
+// values, interpolate into text, and combine with Go templates.
+func TestResolveValue_Expressions(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  interface{}
+	}{
+		{"number", "${{ len(item.angles) * 2 }}", 6},
+		{"bool", "${{ index > 2 }}", true},
+		{"list", "${{ filter(item.angles, # > 0) }}", []interface{}{90.0, 180.0}},
+		{"map", `${{ {"name": upper(item.name)} }}`, map[string]interface{}{"name": "CHAIR"}},
+		{"interpolated", "${{ item.name }}-${{ index + 1 }}.png", "chair-4.png"},
+		{"with template", "{{.item.name}}_${{ index * 10 }}", "chair_30"},
+		{"node ID with dash", `${{ $env["read-csv"].count }}`, 2},
+		{"nested map", `${{ {"size": {"angles": len(item.angles)}} }}`, map[string]interface{}{
+			"size": map[string]interface{}{"angles": 3},
+		}},
+		{"closure", "${{ map(item.angles, {# / 90}) }}", []interface{}{0.0, 1.0, 2.0}},
+		{"braces in string", `${{ item.name + "}}" }}`, "chair}}"},
+		{"interpolated map", `angle-${{ {"a": {"b": index}}.a.b }}.png`, "angle-3.png"},
+	}
+
+	ctx := newExecutionContext()
+	ctx.set("item", map[string]interface{}{"name": "chair", "angles": []interface{}{0.0, 90.0, 180.0}})
+	ctx.set("index", 3)
+	ctx.set("read-csv", map[string]interface{}{"count": 2})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ctx.resolveParam("count", tt.value)
+			if err != nil {
+				t.Fatalf("resolveParam failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestResolveParams_StrictExpression verifies a failing expression is a
+// parameter error in strict mode and left as it is otherwise.
+func TestResolveParams_StrictExpression(t *testing.T) {
+	ctx := newExecutionContext()
+	ctx.set("item", map[string]interface{}{"name": "chair"})
+	value := "${{ len(itme.angles) }}"
+
+	if got := ctx.resolveValue(value); got != value {
+		t.Errorf("lenient: got %#v, want %q", got, value)
+	}
+
+	ctx.strict = true
+	_, err := ctx.resolveParam("count", value)
+	if err == nil || !strings.Contains(err.Error(), `parameter 'count': cannot resolve "${{ len(itme.angles) }}"`) {
+		t.Errorf("strict: got error %v", err)
 	}
 }
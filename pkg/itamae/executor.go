@@ -2,6 +2,7 @@ package itamae
 
 import (
 	"context"
+	"time"
 
 	"github.com/Develonaut/bento/pkg/neta"
 )
@@ -16,17 +17,36 @@ func (i *Itamae) executeNode(
 	if err := i.checkContextCancellation(ctx); err != nil {
 		return err
 	}
-	return i.dispatchNodeType(ctx, def, execCtx, result)
+	if i.restoreCheckpointedNode(def, execCtx, result) {
+		return nil
+	}
+	skip, err := i.whenSkips(def, execCtx)
+	if err != nil {
+		return err
+	}
+	if skip {
+		i.skipNodeWhen(def, execCtx, result)
+		return nil
+	}
+
+	ctx, release, err := i.acquireResource(ctx, def)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, span := i.startNodeSpan(ctx, def, execCtx)
+	err = i.executeWithTimeout(ctx, def, func(ctx context.Context) error {
+		return i.dispatchNodeType(ctx, def, execCtx, result)
+	})
+	i.endSpan(span, err)
+	return err
 }
 
-// checkContextCancellation checks if context is cancelled.
+// checkContextCancellation checks if context is cancelled or the run is
+// stopping, in which case no new node may start.
 func (i *Itamae) checkContextCancellation(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		return nil
-	}
+	return stopErr(ctx)
 }
 
 // dispatchNodeType routes to appropriate executor based on node type.
@@ -43,6 +63,8 @@ func (i *Itamae) dispatchNodeType(
 		return i.executeLoop(ctx, def, execCtx, result)
 	case "parallel":
 		return i.executeParallel(ctx, def, execCtx, result)
+	case "bento":
+		return i.executeSubBento(ctx, def, execCtx, result)
 	default:
 		return i.executeSingle(ctx, def, execCtx, result)
 	}
@@ -95,11 +117,22 @@ func (i *Itamae) executeAndMarkNode(
 	execCtx *executionContext,
 	result *Result,
 ) error {
-	if err := i.executeNode(ctx, node, execCtx, result); err != nil {
+	if g.shouldSkip(node.ID) {
+		i.skipNode(node, execCtx, result)
+		executed[node.ID] = true
+		g.markSkipped(node.ID)
+		return nil
+	}
+	nodeCtx, err := i.withGraphInputs(g, node, execCtx)
+	if err != nil {
+		i.notifyGraphError(node.ID, time.Now(), err)
+		return err
+	}
+	if err := i.executeNode(ctx, node, nodeCtx, result); err != nil {
 		return err
 	}
 	executed[node.ID] = true
-	g.markExecuted(node.ID)
+	markNodeExecuted(g, node, result)
 	return nil
 }
 
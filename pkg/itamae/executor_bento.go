@@ -0,0 +1,293 @@
+package itamae
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/omakase"
+)
+
+// maxBentoDepth limits how deeply sub-bentos can call each other.
+// Exceeding it almost always means a bento (indirectly) calls itself.
+const maxBentoDepth = 10
+
+// BentoLoader loads stored bentos by name for the "bento" neta.
+// hangiri.Storage satisfies this interface.
+type BentoLoader interface {
+	LoadBento(ctx context.Context, name string) (*neta.Definition, error)
+}
+
+// BentoValidator validates sub-bentos before they run.
+// omakase.Validator satisfies this interface.
+type BentoValidator interface {
+	Validate(ctx context.Context, def *neta.Definition) error
+}
+
+// SetBentoLoader sets the loader used to resolve sub-bentos by name.
+func (i *Itamae) SetBentoLoader(loader BentoLoader) {
+	i.loader = loader
+}
+
+// SetBentoValidator sets the validator sub-bentos must pass before they run.
+func (i *Itamae) SetBentoValidator(validator BentoValidator) {
+	i.validator = validator
+}
+
+// SetBentoDir sets the directory that relative sub-bento paths and files
+// loop patterns resolve against.
+// Usually the directory of the bento file being served.
+func (i *Itamae) SetBentoDir(dir string) {
+	i.bentoDir = dir
+}
+
+// executeSubBento runs another bento as a single node.
+//
+// Parameters:
+//   - bento: name of a stored bento (loaded via the BentoLoader), or
+//   - path: bento file path, relative to the calling bento's directory
+//   - inputs: values set in the sub-bento's context (e.g. {{.productDir}})
+//
+// The node's output is the sub-bento's declared "outputs", resolved against
+// the sub-bento's node outputs.
+func (i *Itamae) executeSubBento(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+) error {
+	i.logExecutionStart(def, execCtx)
+	start := time.Now()
+
+	var childResult *Result
+	output, err := i.executeWithRetry(ctx, def, execCtx, func() (interface{}, error) {
+		var output interface{}
+		var err error
+		output, childResult, err = i.runSubBento(ctx, def, execCtx)
+		return output, err
+	})
+
+	duration := time.Since(start)
+	i.sendNodeCompleted(def.ID, duration, err)
+	if err != nil {
+		i.state.setNodeState(def.ID, "error")
+		return newNodeError(def.ID, def.Type, "execute", err)
+	}
+
+	i.storeExecutionResult(def, output, execCtx, result)
+	result.NodesExecuted += childResult.NodesExecuted
+	result.NodesSkipped += childResult.NodesSkipped
+	result.NodesCached += childResult.NodesCached
+	result.LoopFailures = append(result.LoopFailures, childResult.LoopFailures...)
+	i.logExecutionComplete(def, execCtx, duration)
+	return nil
+}
+
+// runSubBento loads the sub-bento and executes it in a child context.
+func (i *Itamae) runSubBento(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+) (map[string]interface{}, *Result, error) {
+	if execCtx.bentoDepth >= maxBentoDepth {
+		return nil, nil, fmt.Errorf("sub-bentos nested deeper than %d levels (does a bento call itself?)", maxBentoDepth)
+	}
+
+	params, err := execCtx.resolveParams(def.Parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	child, bentoDir, err := i.loadSubBento(ctx, params, execCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if i.validator != nil {
+		if err := i.validator.Validate(ctx, child); err != nil {
+			return nil, nil, fmt.Errorf("invalid sub-bento: %w", err)
+		}
+	}
+
+	// Inputs fill the sub-bento's declared variables, which fall back to their defaults
+	inputs, _ := params["inputs"].(map[string]interface{})
+	vars, err := omakase.ResolveVariables(child.Variables, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	i.resources.declare(child.Resources)
+	childCtx := newSubBentoContext(def, vars, execCtx, bentoDir)
+	childResult := &Result{
+		NodeOutputs: make(map[string]interface{}),
+	}
+	if err := i.subChef(def, child).executeNode(ctx, child, childCtx, childResult); err != nil {
+		return nil, nil, err
+	}
+
+	outputs, err := subBentoOutputs(child, childCtx, childResult)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outputs, childResult, nil
+}
+
+// loadSubBento loads the sub-bento named by the "path" or "bento" parameter.
+// Returns the definition and the directory its own relative paths resolve against.
+func (i *Itamae) loadSubBento(
+	ctx context.Context,
+	params map[string]interface{},
+	execCtx *executionContext,
+) (*neta.Definition, string, error) {
+	if path, ok := params["path"].(string); ok && path != "" {
+		if !filepath.IsAbs(path) && execCtx.bentoDir != "" {
+			path = filepath.Join(execCtx.bentoDir, path)
+		}
+		child, err := loadBentoFile(path)
+		return child, filepath.Dir(path), err
+	}
+
+	name, ok := params["bento"].(string)
+	if !ok || name == "" {
+		return nil, "", fmt.Errorf("'bento' or 'path' parameter is required")
+	}
+	if i.loader == nil {
+		return nil, "", fmt.Errorf("cannot load bento '%s': no bento loader configured", name)
+	}
+	child, err := i.loader.LoadBento(ctx, name)
+	return child, execCtx.bentoDir, err
+}
+
+// loadBentoFile loads a bento definition from a JSON file.
+func loadBentoFile(path string) (*neta.Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bento: %w", err)
+	}
+
+	var def neta.Definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse bento '%s': %w", path, err)
+	}
+	return &def, nil
+}
+
+// newSubBentoContext creates the sub-bento's context holding vars.
+// Only the explicit inputs are passed down; parent node outputs are not visible.
+// The breadcrumb continues from the calling node.
+func newSubBentoContext(
+	def *neta.Definition,
+	vars map[string]interface{},
+	execCtx *executionContext,
+	bentoDir string,
+) *executionContext {
+	childCtx := execCtx.withEnv()
+	childCtx.depth = execCtx.depth + 1
+	childCtx.path = append(append([]string{}, execCtx.path...), def.Name)
+	childCtx.bentoDir = bentoDir
+	childCtx.bentoDepth = execCtx.bentoDepth + 1
+	childCtx.setVariables(vars)
+	return childCtx
+}
+
+// subChef creates the itamae that executes a sub-bento.
+// It tracks progress separately (node IDs are only unique per bento) and
+// reports progress into the calling node. Checkpoints apply to the calling
+// node as a whole.
+func (i *Itamae) subChef(def *neta.Definition, child *neta.Definition) *Itamae {
+	graph := analyzeGraph(child)
+	sub := &Itamae{
+		pantry:      i.pantry,
+		logger:      i.logger,
+		slowMoDelay: i.slowMoDelay,
+		state:       newExecutionState(graph),
+		cache:       i.cache,
+		loader:      i.loader,
+		validator:   i.validator,
+		tracer:      i.tracer,
+		lenient:     i.lenient,
+		gracePeriod: i.gracePeriod,
+		resources:   i.resources,
+	}
+
+	sub.onProgress = func(nodeID, status string) {
+		i.state.setNodeProgress(def.ID, sub.state.getProgress(), "Running")
+	}
+	if i.messenger != nil {
+		sub.messenger = &subBentoMessenger{parent: i.messenger, path: def.ID, total: len(graph.Nodes)}
+	}
+	return sub
+}
+
+// subBentoOutputs resolves the sub-bento's declared outputs.
+func subBentoOutputs(
+	child *neta.Definition,
+	childCtx *executionContext,
+	childResult *Result,
+) (map[string]interface{}, error) {
+	outCtx := childCtx.copy()
+	for nodeID, output := range childResult.NodeOutputs {
+		outCtx.set(nodeID, output)
+	}
+
+	outputs := make(map[string]interface{}, len(child.Outputs))
+	for name, value := range child.Outputs {
+		resolved, err := outCtx.resolveParam("outputs."+name, value)
+		if err != nil {
+			return nil, err
+		}
+		outputs[name] = resolved
+	}
+	return outputs, nil
+}
+
+// subBentoMessenger shows a sub-bento's nodes as children of the calling node,
+// the same way loop children are shown.
+type subBentoMessenger struct {
+	parent  ProgressMessenger
+	path    string // Path of the calling node
+	total   int    // Number of nodes in the sub-bento
+	mu      sync.Mutex
+	started int
+}
+
+// SendNodeStarted reports a sub-bento node as the calling node's current child.
+// Groups are transparent, as in the TUI.
+func (m *subBentoMessenger) SendNodeStarted(path, name, nodeType string) {
+	if nodeType == "group" || nodeType == "parallel" {
+		return
+	}
+
+	m.mu.Lock()
+	index := m.started
+	m.started++
+	m.mu.Unlock()
+
+	m.parent.SendLoopChild(m.path, name, index, m.total)
+}
+
+// SendNodeCompleted is a no-op (the calling node reports completion).
+func (m *subBentoMessenger) SendNodeCompleted(path string, duration time.Duration, err error) {}
+
+// SendLoopChild is a no-op (loops inside a sub-bento are one child).
+func (m *subBentoMessenger) SendLoopChild(loopPath, childName string, index, total int) {}
+
+// SendNodeSkipped is a no-op.
+func (m *subBentoMessenger) SendNodeSkipped(path, name, nodeType string) {}
+
+// SendNodeRetry is a no-op (retries are logged with the sub-bento breadcrumb).
+func (m *subBentoMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {}
+
+// SendNodeCached is a no-op.
+func (m *subBentoMessenger) SendNodeCached(path string) {}
+
+// SendNodeOutput forwards streamed lines as output of the calling node.
+func (m *subBentoMessenger) SendNodeOutput(path, line string) {
+	if out, ok := m.parent.(OutputMessenger); ok {
+		out.SendNodeOutput(m.path, line)
+	}
+}
@@ -0,0 +1,65 @@
+package itamae
+
+import (
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/neta/library/branch"
+)
+
+// branchTypes are the node types whose output selects an active sourceHandle.
+var branchTypes = map[string]bool{
+	"if":     true,
+	"switch": true,
+}
+
+// markNodeExecuted resolves a finished node's outgoing edges.
+// Branch nodes fire only the edges of the handle they selected. Without a
+// handle (e.g. skipped by when) or with one no edge has, only the "default"
+// handle fires.
+func markNodeExecuted(g *graph, def *neta.Definition, result *Result) {
+	if !branchTypes[def.Type] {
+		g.markExecuted(def.ID)
+		return
+	}
+	g.markBranchExecuted(def.ID, selectedHandle(def, result), branch.DefaultHandle)
+}
+
+// selectedHandle returns the sourceHandle a branch node selected ("" if none).
+func selectedHandle(def *neta.Definition, result *Result) string {
+	output, ok := result.NodeOutputs[def.ID].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	handle, _ := output["handle"].(string)
+	return handle
+}
+
+// skipNode records a node reached only through inactive branch edges.
+// Skipped groups report every node inside them as skipped.
+func (i *Itamae) skipNode(def *neta.Definition, execCtx *executionContext, result *Result) {
+	result.NodesSkipped++
+
+	if i.logger != nil {
+		msg := msgNodeSkipped(execCtx.getBreadcrumb(), def.Type, def.Name)
+		i.logger.Info(msg.format())
+	}
+
+	i.reportSkipped(def)
+}
+
+// reportSkipped marks a node (and any flattened children) as skipped.
+func (i *Itamae) reportSkipped(def *neta.Definition) {
+	if def.Type == "group" || def.Type == "parallel" {
+		for _, nodes := range [][]neta.Definition{def.Nodes, def.Catch, def.OnCancel, def.Finally} {
+			for idx := range nodes {
+				i.reportSkipped(&nodes[idx])
+			}
+		}
+		return
+	}
+
+	i.state.setNodeState(def.ID, "skipped")
+	if i.messenger != nil {
+		i.messenger.SendNodeSkipped(def.ID, def.Name, def.Type)
+	}
+	i.notifyProgress(def.ID, "skipped")
+}
@@ -0,0 +1,121 @@
+package itamae
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// cacheHitsKey is the context key of the counter of cache hits inside a
+// loop, whose children have no result of their own to count them in.
+type cacheHitsKey struct{}
+
+// withCacheHits returns ctx counting the cache hits of the loop children run on it.
+func withCacheHits(ctx context.Context) (context.Context, *atomic.Int64) {
+	hits := new(atomic.Int64)
+	return context.WithValue(ctx, cacheHitsKey{}, hits), hits
+}
+
+// SetCache enables output caching for nodes that opt in with "cache": true.
+func (i *Itamae) SetCache(cache OutputCache) {
+	i.cache = cache
+}
+
+// nodeCacheKey returns the cache key for a node ("" when caching doesn't apply).
+// Nodes whose parameters can't be hashed simply run uncached.
+func (i *Itamae) nodeCacheKey(def *neta.Definition, params map[string]interface{}) string {
+	if i.cache == nil || !def.Cache {
+		return ""
+	}
+
+	key, err := cacheKey(def, params)
+	if err != nil {
+		if i.logger != nil {
+			i.logger.Warn("Node output will not be cached",
+				"node_id", def.ID,
+				"error", err)
+		}
+		return ""
+	}
+	return key
+}
+
+// cachedOutput looks up a node's output in the cache and logs hits.
+func (i *Itamae) cachedOutput(def *neta.Definition, key string, execCtx *executionContext) (interface{}, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	output, ok := i.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	if i.logger != nil {
+		msg := msgNodeCached(execCtx.getBreadcrumb(), def.Type, def.Name)
+		i.logger.Info(msg.format())
+	}
+	return output, true
+}
+
+// restoreCachedNode reuses a cached output instead of executing the node.
+func (i *Itamae) restoreCachedNode(def *neta.Definition, key string, execCtx *executionContext, result *Result) bool {
+	output, ok := i.cachedOutput(def, key, execCtx)
+	if !ok {
+		return false
+	}
+
+	execCtx.set(def.ID, output)
+	result.NodeOutputs[def.ID] = output
+	result.NodesCached++
+	i.checkpointNode(def, output)
+
+	i.state.setNodeProgress(def.ID, 100, "Cached")
+	i.state.setNodeState(def.ID, "completed")
+
+	if i.messenger != nil {
+		i.messenger.SendNodeCached(def.ID)
+	}
+	i.notifyProgress(def.ID, "completed")
+	return true
+}
+
+// countCacheHits adds n cache hits to the loop running on ctx, if any.
+func countCacheHits(ctx context.Context, n int) {
+	if hits, ok := ctx.Value(cacheHitsKey{}).(*atomic.Int64); ok {
+		hits.Add(int64(n))
+	}
+}
+
+// restoreCachedChild reuses a cached output for a loop child, counting the
+// hit for its loop.
+func (i *Itamae) restoreCachedChild(
+	ctx context.Context,
+	def *neta.Definition,
+	key string,
+	execCtx *executionContext,
+) (interface{}, bool) {
+	output, ok := i.cachedOutput(def, key, execCtx)
+	if !ok {
+		return nil, false
+	}
+
+	countCacheHits(ctx, 1)
+	if i.messenger != nil {
+		i.messenger.SendNodeCached(def.ID)
+	}
+	return output, true
+}
+
+// cacheOutput stores a node's output. Cache failures are logged but never fail the run.
+func (i *Itamae) cacheOutput(def *neta.Definition, key string, output interface{}) {
+	if key == "" {
+		return
+	}
+	if err := i.cache.Put(key, def.ID, def.Type, output); err != nil && i.logger != nil {
+		i.logger.Warn("Failed to cache node output",
+			"node_id", def.ID,
+			"error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package itamae
+
+import (
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// SetCheckpoint enables checkpointing for subsequent runs.
+// Completed node outputs and loop iterations are written to the checkpoint,
+// and any outputs already in it are reused instead of re-executing the node.
+func (i *Itamae) SetCheckpoint(cp *Checkpoint) {
+	i.checkpoint = cp
+}
+
+// restoreCheckpointedNode reuses a checkpointed output instead of executing the node.
+// Groups and parallels are never restored as a whole - their children are.
+func (i *Itamae) restoreCheckpointedNode(def *neta.Definition, execCtx *executionContext, result *Result) bool {
+	if i.checkpoint == nil || def.Type == "group" || def.Type == "parallel" {
+		return false
+	}
+
+	hash, err := definitionHash(def)
+	if err != nil {
+		return false
+	}
+	output, ok := i.checkpoint.node(def.ID, hash)
+	if !ok {
+		return false
+	}
+
+	execCtx.set(def.ID, output)
+	result.NodeOutputs[def.ID] = output
+	result.NodesRestored++
+
+	i.state.setNodeProgress(def.ID, 100, "Restored")
+	i.state.setNodeState(def.ID, "completed")
+
+	if i.messenger != nil {
+		i.messenger.SendNodeStarted(def.ID, def.Name, def.Type)
+		i.messenger.SendNodeCompleted(def.ID, 0, nil)
+	}
+	i.notifyProgress(def.ID, "completed")
+
+	if i.logger != nil {
+		msg := msgNodeRestored(execCtx.getBreadcrumb(), def.Type, def.Name)
+		i.logger.Info(msg.format())
+	}
+	return true
+}
+
+// checkpointNode persists a completed node's output.
+// Checkpoint failures are logged but never fail the run.
+func (i *Itamae) checkpointNode(def *neta.Definition, output interface{}) {
+	if i.checkpoint == nil {
+		return
+	}
+	hash, err := definitionHash(def)
+	if err == nil {
+		err = i.checkpoint.saveNode(def.ID, hash, output)
+	}
+	if err != nil && i.logger != nil {
+		i.logger.Warn("Failed to checkpoint node output",
+			"node_id", def.ID,
+			"error", err)
+	}
+}
+
+// restoredIteration returns a checkpointed loop iteration result if one was
+// produced by the same loop definition and item.
+func (i *Itamae) restoredIteration(def *neta.Definition, idx int, item interface{}) (map[string]interface{}, bool) {
+	if i.checkpoint == nil {
+		return nil, false
+	}
+	hash, err := iterationHash(def, item)
+	if err != nil {
+		return nil, false
+	}
+	return i.checkpoint.iteration(def.ID, idx, hash)
+}
+
+// checkpointIteration persists a finished loop iteration's output.
+func (i *Itamae) checkpointIteration(def *neta.Definition, idx int, item interface{}, output map[string]interface{}) {
+	if i.checkpoint == nil {
+		return
+	}
+	hash, err := iterationHash(def, item)
+	if err == nil {
+		err = i.checkpoint.saveIteration(def.ID, idx, hash, output)
+	}
+	if err != nil && i.logger != nil {
+		i.logger.Warn("Failed to checkpoint loop iteration",
+			"loop_id", def.ID,
+			"iteration", idx,
+			"error", err)
+	}
+}
@@ -0,0 +1,153 @@
+package itamae
+
+import (
+	"context"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// nodeOutcome is the result of a node executed by the concurrent scheduler.
+type nodeOutcome struct {
+	node   *neta.Definition
+	result *Result
+	err    error
+}
+
+// getGroupConcurrency extracts maxConcurrency parameter from group definition.
+func (i *Itamae) getGroupConcurrency(def *neta.Definition) int {
+	if mc, ok := def.Parameters["maxConcurrency"]; ok {
+		if mcInt, ok := mc.(int); ok {
+			return mcInt
+		}
+		if mcFloat, ok := mc.(float64); ok {
+			return int(mcFloat)
+		}
+	}
+	return 1 // Default: sequential
+}
+
+// executeGraphConcurrent executes every ready node concurrently (up to maxConcurrency).
+//
+// Each node runs on a snapshot of the context containing all outputs merged so far
+// (always including its upstream nodes). Outputs are merged back by this scheduler
+// goroutine only, so the shared context and result are never written concurrently.
+// The first failure cancels in-flight siblings and stops new nodes from starting.
+func (i *Itamae) executeGraphConcurrent(
+	ctx context.Context,
+	g *graph,
+	execCtx *executionContext,
+	result *Result,
+	maxConcurrency int,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan nodeOutcome)
+	executed := make(map[string]bool) // Started or skipped nodes
+	ready := g.getStartNodes()
+	running := 0
+	var firstErr error
+
+	for {
+		if firstErr == nil {
+			var err error
+			ready, running, err = i.launchReadyNodes(ctx, g, ready, running, maxConcurrency, executed, execCtx, result, done)
+			if err != nil {
+				firstErr = err
+				cancel()
+			}
+		}
+		if running == 0 {
+			return firstErr
+		}
+
+		outcome := <-done
+		running--
+		if outcome.err != nil {
+			if firstErr == nil {
+				firstErr = outcome.err
+				cancel()
+			}
+			continue
+		}
+
+		mergeNodeOutcome(outcome, execCtx, result)
+		markNodeExecuted(g, outcome.node, result)
+		ready = i.enqueueReadyTargets(g, outcome.node.ID, executed, ready)
+	}
+}
+
+// launchReadyNodes starts ready nodes until the concurrency limit is reached.
+// Skipped nodes are resolved inline. Nothing is started after a failure.
+func (i *Itamae) launchReadyNodes(
+	ctx context.Context,
+	g *graph,
+	ready []*neta.Definition,
+	running int,
+	maxConcurrency int,
+	executed map[string]bool,
+	execCtx *executionContext,
+	result *Result,
+	done chan<- nodeOutcome,
+) ([]*neta.Definition, int, error) {
+	for len(ready) > 0 && running < maxConcurrency {
+		if err := i.checkContextCancellation(ctx); err != nil {
+			return ready, running, err
+		}
+
+		node := ready[0]
+		ready = ready[1:]
+		if executed[node.ID] {
+			continue
+		}
+		executed[node.ID] = true
+
+		if g.shouldSkip(node.ID) {
+			i.skipNode(node, execCtx, result)
+			g.markSkipped(node.ID)
+			ready = i.enqueueReadyTargets(g, node.ID, executed, ready)
+			continue
+		}
+
+		nodeCtx, err := i.withGraphInputs(g, node, execCtx.copy())
+		if err != nil {
+			i.notifyGraphError(node.ID, time.Now(), err)
+			return ready, running, err
+		}
+		running++
+		go i.runGraphNode(ctx, node, nodeCtx, done)
+	}
+	return ready, running, nil
+}
+
+// runGraphNode executes one node on its own context snapshot and reports the outcome.
+func (i *Itamae) runGraphNode(
+	ctx context.Context,
+	node *neta.Definition,
+	nodeCtx *executionContext,
+	done chan<- nodeOutcome,
+) {
+	nodeResult := &Result{
+		NodeOutputs: make(map[string]interface{}),
+	}
+	err := i.executeNode(ctx, node, nodeCtx, nodeResult)
+	done <- nodeOutcome{node: node, result: nodeResult, err: err}
+}
+
+// mergeNodeOutcome merges a finished node into the shared context and result.
+// Only the node's own output enters the context, as in sequential execution.
+func mergeNodeOutcome(outcome nodeOutcome, execCtx *executionContext, result *Result) {
+	if output, ok := outcome.result.NodeOutputs[outcome.node.ID]; ok {
+		execCtx.set(outcome.node.ID, output)
+	}
+
+	for k, v := range outcome.result.NodeOutputs {
+		result.NodeOutputs[k] = v
+	}
+	result.NodesExecuted += outcome.result.NodesExecuted
+	result.NodesRestored += outcome.result.NodesRestored
+	result.NodesSkipped += outcome.result.NodesSkipped
+	result.NodesCached += outcome.result.NodesCached
+	result.LoopFailures = append(result.LoopFailures, outcome.result.LoopFailures...)
+}
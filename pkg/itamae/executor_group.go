@@ -102,6 +102,7 @@ func (i *Itamae) notifyGraphError(nodeID string, start time.Time, err error) {
 }
 
 // executeGroupGraph executes the group's graph in topological order.
+// Groups with maxConcurrency > 1 run independent branches concurrently.
 func (i *Itamae) executeGroupGraph(
 	ctx context.Context,
 	def *neta.Definition,
@@ -111,7 +112,8 @@ func (i *Itamae) executeGroupGraph(
 	start time.Time,
 ) error {
 	childCtx := execCtx.withNode(def.Name)
-	if err := i.executeGraph(ctx, g, childCtx, result); err != nil {
+	err := i.runGroupGraph(ctx, def, g, childCtx, result)
+	if err := i.runErrorHandlers(ctx, def, childCtx, result, err); err != nil {
 		if i.messenger != nil {
 			i.messenger.SendNodeCompleted(def.ID, time.Since(start), err)
 		}
@@ -120,6 +122,20 @@ func (i *Itamae) executeGroupGraph(
 	return nil
 }
 
+// runGroupGraph picks sequential or concurrent scheduling for the group.
+func (i *Itamae) runGroupGraph(
+	ctx context.Context,
+	def *neta.Definition,
+	g *graph,
+	execCtx *executionContext,
+	result *Result,
+) error {
+	if maxConcurrency := i.getGroupConcurrency(def); maxConcurrency > 1 {
+		return i.executeGraphConcurrent(ctx, g, execCtx, result, maxConcurrency)
+	}
+	return i.executeGraph(ctx, g, execCtx, result)
+}
+
 // logGroupComplete logs completion of group execution.
 func (i *Itamae) logGroupComplete(def *neta.Definition, execCtx *executionContext, duration time.Duration) {
 	i.notifyProgress(def.ID, "completed")
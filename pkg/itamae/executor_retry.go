@@ -0,0 +1,239 @@
+package itamae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Retry defaults used when a policy omits backoff or maxDelay.
+const (
+	defaultRetryBackoff  = time.Second
+	defaultRetryMaxDelay = 30 * time.Second
+)
+
+// retryPolicy is the parsed form of neta.RetryPolicy.
+type retryPolicy struct {
+	maxAttempts  int
+	backoff      time.Duration
+	maxDelay     time.Duration
+	errorPattern *regexp.Regexp
+	statusCodes  map[int]bool
+}
+
+// statusCodeError reports an output status code listed in retryOn.statusCodes.
+type statusCodeError struct {
+	code int
+}
+
+// Error returns the error message.
+func (e *statusCodeError) Error() string {
+	return fmt.Sprintf("retryable status code %d", e.code)
+}
+
+// parseRetryPolicy converts a definition's retry block into a retryPolicy.
+// A nil block yields a single-attempt policy.
+func parseRetryPolicy(def *neta.Definition) (*retryPolicy, error) {
+	policy := &retryPolicy{
+		maxAttempts: 1,
+		backoff:     defaultRetryBackoff,
+		maxDelay:    defaultRetryMaxDelay,
+		statusCodes: make(map[int]bool),
+	}
+	if def.Retry == nil {
+		return policy, nil
+	}
+
+	if def.Retry.MaxAttempts < 1 {
+		return nil, fmt.Errorf("retry.maxAttempts must be at least 1, got %d", def.Retry.MaxAttempts)
+	}
+	policy.maxAttempts = def.Retry.MaxAttempts
+
+	var err error
+	if policy.backoff, err = parseRetryDuration("backoff", def.Retry.Backoff, defaultRetryBackoff); err != nil {
+		return nil, err
+	}
+	if policy.maxDelay, err = parseRetryDuration("maxDelay", def.Retry.MaxDelay, defaultRetryMaxDelay); err != nil {
+		return nil, err
+	}
+
+	return policy, parseRetryOn(def.Retry.RetryOn, policy)
+}
+
+// parseRetryDuration parses a retry duration field, falling back to def when empty.
+func parseRetryDuration(field, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("retry.%s is not a valid duration: %w", field, err)
+	}
+	return d, nil
+}
+
+// parseRetryOn fills the error pattern and status codes of a policy.
+func parseRetryOn(retryOn *neta.RetryOn, policy *retryPolicy) error {
+	if retryOn == nil {
+		return nil
+	}
+
+	if retryOn.ErrorPattern != "" {
+		pattern, err := regexp.Compile(retryOn.ErrorPattern)
+		if err != nil {
+			return fmt.Errorf("retry.retryOn.errorPattern is not a valid regular expression: %w", err)
+		}
+		policy.errorPattern = pattern
+	}
+
+	for _, code := range retryOn.StatusCodes {
+		policy.statusCodes[code] = true
+	}
+	return nil
+}
+
+// delay returns the wait before the attempt following the given one (1-based).
+func (p *retryPolicy) delay(attempt int) time.Duration {
+	d := p.backoff
+	for n := 1; n < attempt && d < p.maxDelay; n++ {
+		d *= 2
+	}
+	if d > p.maxDelay {
+		return p.maxDelay
+	}
+	return d
+}
+
+// shouldRetry reports whether a failed attempt is retryable. Nothing is
+// retried once the node's context is done or the run is stopping, but
+// errors like a client's own timeout are.
+func (p *retryPolicy) shouldRetry(ctx context.Context, err error) bool {
+	if stopErr(ctx) != nil {
+		return false
+	}
+
+	var statusErr *statusCodeError
+	if errors.As(err, &statusErr) {
+		return true
+	}
+
+	return p.errorPattern == nil || p.errorPattern.MatchString(err.Error())
+}
+
+// checkStatusCode turns a listed output statusCode into a retryable error.
+func (p *retryPolicy) checkStatusCode(output interface{}) error {
+	if len(p.statusCodes) == 0 {
+		return nil
+	}
+
+	m, ok := output.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var code int
+	switch v := m["statusCode"].(type) {
+	case int:
+		code = v
+	case float64:
+		code = int(v)
+	default:
+		return nil
+	}
+
+	if p.statusCodes[code] {
+		return &statusCodeError{code: code}
+	}
+	return nil
+}
+
+// executeWithRetry runs attempt until it succeeds or the retry policy gives up.
+// Every retry is reported through the messenger and logger with its attempt number.
+func (i *Itamae) executeWithRetry(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	attempt func() (interface{}, error),
+) (interface{}, error) {
+	policy, err := parseRetryPolicy(def)
+	if err != nil {
+		return nil, err
+	}
+
+	for n := 1; ; n++ {
+		span := i.startAttemptSpan(ctx, def, n, policy.maxAttempts)
+		output, err := attempt()
+		if err == nil {
+			err = policy.checkStatusCode(output)
+		}
+		i.endSpan(span, err)
+		if err == nil {
+			return output, nil
+		}
+
+		if n >= policy.maxAttempts || !policy.shouldRetry(ctx, err) {
+			if n > 1 {
+				return nil, fmt.Errorf("failed after %d attempts: %w", n, err)
+			}
+			return nil, err
+		}
+
+		delay := policy.delay(n)
+		i.reportRetry(def, execCtx, n+1, policy.maxAttempts, delay, err)
+		if err := i.waitForRetry(ctx, def, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reportRetry reports an upcoming retry attempt.
+func (i *Itamae) reportRetry(
+	def *neta.Definition,
+	execCtx *executionContext,
+	attempt, maxAttempts int,
+	delay time.Duration,
+	cause error,
+) {
+	if i.messenger != nil {
+		i.messenger.SendNodeRetry(def.ID, attempt, maxAttempts, cause)
+	}
+
+	if i.logger != nil {
+		msg := msgNodeRetrying(execCtx.getBreadcrumb(), def.Type, def.Name, attempt, maxAttempts, formatDuration(delay))
+		i.logger.Warn(msg.format(),
+			"neta_id", def.ID,
+			"attempt", attempt,
+			"max_attempts", maxAttempts,
+			"error", cause)
+	}
+}
+
+// waitForRetry sleeps for the backoff delay unless the context is cancelled
+// or the run is stopping. A resource slot the node holds is given up while
+// it waits.
+func (i *Itamae) waitForRetry(ctx context.Context, def *neta.Definition, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	slot := ownSlot(ctx, def)
+	if slot != nil {
+		slot.release()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-runContext(ctx).Done():
+		return runContext(ctx).Err()
+	case <-timer.C:
+	}
+
+	if slot != nil {
+		return i.reacquireSlot(ctx, slot)
+	}
+	return nil
+}
@@ -35,13 +35,22 @@ func (i *Itamae) loadNetaImplementation(def *neta.Definition) (neta.Executable,
 // executeAndRecordNeta executes neta and records results.
 func (i *Itamae) executeAndRecordNeta(ctx context.Context, def *neta.Definition, netaImpl neta.Executable,
 	execCtx *executionContext, result *Result) error {
-	params := i.prepareNetaParams(def, execCtx)
-	output, duration, err := i.executeNetaWithTiming(ctx, netaImpl, params)
+	params, err := i.prepareNetaParams(def, execCtx)
+	if err != nil {
+		i.sendNodeCompleted(def.ID, 0, err)
+		return newNodeError(def.ID, def.Type, "resolve params", err)
+	}
+	key := i.nodeCacheKey(def, params)
+	if i.restoreCachedNode(def, key, execCtx, result) {
+		return nil
+	}
+	output, duration, err := i.executeNetaWithTiming(ctx, def, netaImpl, params, execCtx)
 	i.sendNodeCompleted(def.ID, duration, err)
 	if err != nil {
 		return newNodeError(def.ID, def.Type, "execute", err)
 	}
-	i.storeExecutionResult(def.ID, output, execCtx, result)
+	i.storeExecutionResult(def, output, execCtx, result)
+	i.cacheOutput(def, key, output)
 	i.logExecutionComplete(def, execCtx, duration)
 	return nil
 }
@@ -67,14 +76,25 @@ func (i *Itamae) logExecutionStart(def *neta.Definition, execCtx *executionConte
 }
 
 // prepareNetaParams prepares execution parameters with context resolution.
-func (i *Itamae) prepareNetaParams(def *neta.Definition, execCtx *executionContext) map[string]interface{} {
-	params := make(map[string]interface{})
-	for k, v := range def.Parameters {
-		params[k] = execCtx.resolveValue(v)
+func (i *Itamae) prepareNetaParams(def *neta.Definition, execCtx *executionContext) (map[string]interface{}, error) {
+	params, err := execCtx.resolveParams(def.Parameters)
+	if err != nil {
+		return nil, err
 	}
 
 	params["_context"] = execCtx.toMap()
-	params["_onOutput"] = func(line string) {
+	params["_onOutput"] = i.streamOutput(def, execCtx)
+	if execCtx.inputs != nil {
+		params["_inputs"] = execCtx.inputs
+	}
+
+	return params, nil
+}
+
+// streamOutput returns the _onOutput callback for a node.
+// Lines are logged with the breadcrumb and forwarded to an OutputMessenger.
+func (i *Itamae) streamOutput(def *neta.Definition, execCtx *executionContext) func(string) {
+	return func(line string) {
 		if i.logger != nil {
 			// Stream output with breadcrumb context (no tree indentation)
 			// This is used for Blender/external process output
@@ -86,19 +106,24 @@ func (i *Itamae) prepareNetaParams(def *neta.Definition, execCtx *executionConte
 				i.logger.Info(line)
 			}
 		}
+		if out, ok := i.messenger.(OutputMessenger); ok {
+			out.SendNodeOutput(def.ID, line)
+		}
 	}
-
-	return params
 }
 
-// executeNetaWithTiming executes a neta and tracks duration.
+// executeNetaWithTiming executes a neta (with its retry policy) and tracks duration.
 func (i *Itamae) executeNetaWithTiming(
 	ctx context.Context,
+	def *neta.Definition,
 	netaImpl neta.Executable,
 	params map[string]interface{},
+	execCtx *executionContext,
 ) (interface{}, time.Duration, error) {
 	start := time.Now()
-	output, err := netaImpl.Execute(ctx, params)
+	output, err := i.executeWithRetry(ctx, def, execCtx, func() (interface{}, error) {
+		return netaImpl.Execute(ctx, params)
+	})
 	duration := time.Since(start)
 
 	if i.slowMoDelay > 0 {
@@ -117,18 +142,19 @@ func (i *Itamae) sendNodeCompleted(nodeID string, duration time.Duration, err er
 
 // storeExecutionResult stores node output and marks node as completed.
 func (i *Itamae) storeExecutionResult(
-	nodeID string,
+	def *neta.Definition,
 	output interface{},
 	execCtx *executionContext,
 	result *Result,
 ) {
-	execCtx.set(nodeID, output)
-	result.NodeOutputs[nodeID] = output
+	execCtx.set(def.ID, output)
+	result.NodeOutputs[def.ID] = output
 	result.NodesExecuted++
+	i.checkpointNode(def, output)
 
 	// Mark node as completed in execution state
-	i.state.setNodeProgress(nodeID, 100, "Completed")
-	i.state.setNodeState(nodeID, "completed")
+	i.state.setNodeProgress(def.ID, 100, "Completed")
+	i.state.setNodeState(def.ID, "completed")
 }
 
 // logExecutionComplete logs completion with progress tracking.
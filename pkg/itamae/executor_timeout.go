@@ -0,0 +1,73 @@
+package itamae
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// TimeoutError reports a node that exceeded its timeout.
+// It unwraps to context.DeadlineExceeded.
+type TimeoutError struct {
+	NodeID string        // ID of the node that timed out
+	Limit  time.Duration // The node's configured timeout
+}
+
+// Error returns the error message.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("node '%s' timed out after %s", e.NodeID, e.Limit)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// parseNodeTimeout parses a definition's timeout field (0 = no limit).
+func parseNodeTimeout(def *neta.Definition) (time.Duration, error) {
+	if def.Timeout == "" {
+		return 0, nil
+	}
+	limit, err := time.ParseDuration(def.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("timeout is not a valid duration: %w", err)
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %s", def.Timeout)
+	}
+	return limit, nil
+}
+
+// executeWithTimeout runs a node under its own deadline if it declares a timeout.
+// Exceeding the deadline returns a *TimeoutError naming the node and the limit.
+func (i *Itamae) executeWithTimeout(
+	ctx context.Context,
+	def *neta.Definition,
+	run func(ctx context.Context) error,
+) error {
+	limit, err := parseNodeTimeout(def)
+	if err != nil {
+		return newNodeError(def.ID, def.Type, "validate", err)
+	}
+	if limit == 0 {
+		return run(ctx)
+	}
+
+	nodeCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	err = run(nodeCtx)
+	if err == nil || nodeCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return err
+	}
+
+	// A nested node may have timed out first - keep the innermost report.
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return err
+	}
+	return &TimeoutError{NodeID: def.ID, Limit: limit}
+}
@@ -0,0 +1,134 @@
+package itamae
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// caughtErrorKey is the context key that exposes a group failure to its catch nodes.
+// Templates can use {{.error.nodeId}}, {{.error.nodeType}} and {{.error.message}}.
+const caughtErrorKey = "error"
+
+// runErrorHandlers runs a group's catch, onCancel and finally nodes after its
+// main branch. A successful catch branch handles err. onCancel nodes run when
+// the run was cancelled. Finally nodes always run, even once the run is
+// stopped; their error is joined with any error still unhandled.
+func (i *Itamae) runErrorHandlers(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+	err error,
+) error {
+	if len(def.Catch) == 0 && len(def.OnCancel) == 0 && len(def.Finally) == 0 {
+		return err
+	}
+
+	cleanupCtx := cleanupContext(ctx)
+	err = i.runCatch(ctx, def, execCtx, result, err)
+	if cancelErr := i.runOnCancel(ctx, cleanupCtx, def, execCtx, result, err); cancelErr != nil {
+		err = errors.Join(err, cancelErr)
+	}
+	if finallyErr := i.executeHandlerNodes(cleanupCtx, def.Finally, execCtx, result); finallyErr != nil {
+		return errors.Join(err, finallyErr)
+	}
+	return err
+}
+
+// runOnCancel executes the onCancel nodes when the run was cancelled while the
+// group ran. Otherwise they are reported as skipped.
+func (i *Itamae) runOnCancel(
+	ctx context.Context,
+	cleanupCtx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+	err error,
+) error {
+	if len(def.OnCancel) == 0 {
+		return nil
+	}
+	if err == nil || !runCancelled(ctx) {
+		for idx := range def.OnCancel {
+			i.skipNode(&def.OnCancel[idx], execCtx, result)
+		}
+		return nil
+	}
+
+	if i.logger != nil {
+		msg := msgGroupCancelled(execCtx.getBreadcrumb(), def.Name)
+		i.logger.Warn(msg.format())
+	}
+	return i.executeHandlerNodes(cleanupCtx, def.OnCancel, execCtx, result)
+}
+
+// runCatch executes the catch nodes when the main branch failed.
+// When it succeeded they are reported as skipped. Cancellation is never caught.
+func (i *Itamae) runCatch(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+	err error,
+) error {
+	if len(def.Catch) == 0 {
+		return err
+	}
+	if err == nil {
+		for idx := range def.Catch {
+			i.skipNode(&def.Catch[idx], execCtx, result)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	caught := caughtError(err)
+	if i.logger != nil {
+		msg := msgGroupCaught(execCtx.getBreadcrumb(), def.Name, caught["nodeId"].(string))
+		i.logger.Warn(msg.format(), "error", err)
+	}
+
+	execCtx.set(caughtErrorKey, caught)
+	return i.executeHandlerNodes(ctx, def.Catch, execCtx, result)
+}
+
+// executeHandlerNodes runs catch, onCancel or finally nodes sequentially in declaration order.
+func (i *Itamae) executeHandlerNodes(
+	ctx context.Context,
+	nodes []neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+) error {
+	for idx := range nodes {
+		if err := i.executeNode(ctx, &nodes[idx], execCtx, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// caughtError describes the innermost failing node of err for catch nodes.
+func caughtError(err error) map[string]interface{} {
+	caught := map[string]interface{}{
+		"nodeId":   "",
+		"nodeType": "",
+		"message":  err.Error(),
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch failed := e.(type) {
+		case *nodeError:
+			caught["nodeId"] = failed.nodeID
+			caught["nodeType"] = failed.nodeType
+			caught["message"] = failed.cause.Error()
+		case *TimeoutError:
+			caught["nodeId"] = failed.NodeID
+			caught["message"] = failed.Error()
+		}
+	}
+	return caught
+}
@@ -0,0 +1,43 @@
+package itamae
+
+import (
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// whenSkips evaluates a node's when condition against the context.
+// Returns true when the node declares a condition and it is false.
+func (i *Itamae) whenSkips(def *neta.Definition, execCtx *executionContext) (bool, error) {
+	if def.When == "" {
+		return false, nil
+	}
+
+	run, err := execCtx.evaluateCondition(def.When)
+	if err != nil {
+		return false, newNodeError(def.ID, def.Type, "evaluate when", err)
+	}
+	return !run, nil
+}
+
+// skipNodeWhen records a node whose when condition is false.
+// Unlike nodes on inactive branches, its downstream nodes still run.
+func (i *Itamae) skipNodeWhen(def *neta.Definition, execCtx *executionContext, result *Result) {
+	result.NodesSkipped++
+
+	if i.logger != nil {
+		msg := msgNodeSkippedWhen(execCtx.getBreadcrumb(), def.Type, def.Name, def.When)
+		i.logger.Info(msg.format())
+	}
+
+	i.reportSkipped(def)
+}
+
+// skipLoopChildWhen reports whether a loop child's when condition is false
+// for the current iteration, logging the skip.
+func (i *Itamae) skipLoopChildWhen(def *neta.Definition, iterCtx *executionContext) (bool, error) {
+	skip, err := i.whenSkips(def, iterCtx)
+	if skip && i.logger != nil {
+		msg := msgNodeSkippedWhen(iterCtx.getBreadcrumb(), def.Type, def.Name, def.When)
+		i.logger.Info(msg.format())
+	}
+	return skip, err
+}
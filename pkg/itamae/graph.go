@@ -9,16 +9,28 @@ import (
 // graph represents a directed graph for node execution order.
 type graph struct {
 	nodes    map[string]*neta.Definition // Node ID -> Definition
-	edges    map[string][]string         // Node ID -> List of target IDs
-	incoming map[string]int              // Node ID -> Count of incoming edges
+	edges    map[string][]graphEdge      // Node ID -> Outgoing edges
+	incoming map[string]int              // Node ID -> Count of unresolved incoming edges
+	inDegree map[string]int              // Node ID -> Total count of incoming edges
+	active   map[string]int              // Node ID -> Count of incoming edges that fired
+	inputs   map[string][]neta.Edge      // Node ID -> Incoming edges that feed a named input
+}
+
+// graphEdge is an outgoing edge with the source handle it belongs to.
+type graphEdge struct {
+	target       string // Target node ID
+	sourceHandle string // Source handle ("" fires regardless of branch result)
 }
 
 // buildGraph creates a graph from a bento definition.
 func buildGraph(def *neta.Definition) (*graph, error) {
 	g := &graph{
 		nodes:    make(map[string]*neta.Definition),
-		edges:    make(map[string][]string),
+		edges:    make(map[string][]graphEdge),
 		incoming: make(map[string]int),
+		inDegree: make(map[string]int),
+		active:   make(map[string]int),
+		inputs:   make(map[string][]neta.Edge),
 	}
 
 	// Add all nodes
@@ -38,8 +50,15 @@ func buildGraph(def *neta.Definition) (*graph, error) {
 			return nil, fmt.Errorf("edge target '%s' not found", edge.Target)
 		}
 
-		g.edges[edge.Source] = append(g.edges[edge.Source], edge.Target)
+		g.edges[edge.Source] = append(g.edges[edge.Source], graphEdge{
+			target:       edge.Target,
+			sourceHandle: edge.SourceHandle,
+		})
 		g.incoming[edge.Target]++
+		g.inDegree[edge.Target]++
+		if edge.TargetHandle != "" {
+			g.inputs[edge.Target] = append(g.inputs[edge.Target], edge)
+		}
 	}
 
 	return g, nil
@@ -60,14 +79,14 @@ func (g *graph) getStartNodes() []*neta.Definition {
 
 // getTargets returns all target nodes for a given source node.
 func (g *graph) getTargets(nodeID string) []*neta.Definition {
-	targetIDs := g.edges[nodeID]
-	if len(targetIDs) == 0 {
+	edges := g.edges[nodeID]
+	if len(edges) == 0 {
 		return nil
 	}
 
-	targets := make([]*neta.Definition, 0, len(targetIDs))
-	for _, targetID := range targetIDs {
-		if node, ok := g.nodes[targetID]; ok {
+	targets := make([]*neta.Definition, 0, len(edges))
+	for _, edge := range edges {
+		if node, ok := g.nodes[edge.target]; ok {
 			targets = append(targets, node)
 		}
 	}
@@ -76,12 +95,51 @@ func (g *graph) getTargets(nodeID string) []*neta.Definition {
 }
 
 // markExecuted marks a node as executed and decrements incoming edge counts.
+// All of its outgoing edges fire.
 func (g *graph) markExecuted(nodeID string) {
-	for _, targetID := range g.edges[nodeID] {
-		g.incoming[targetID]--
+	for _, edge := range g.edges[nodeID] {
+		g.incoming[edge.target]--
+		g.active[edge.target]++
+	}
+}
+
+// markBranchExecuted marks a branch node as executed. Only the edges of the
+// selected handle fire, or those of fallback when no edge has that handle.
+// Edges without a sourceHandle always fire.
+func (g *graph) markBranchExecuted(nodeID string, handle string, fallback string) {
+	if !g.hasHandle(nodeID, handle) {
+		handle = fallback
+	}
+	for _, edge := range g.edges[nodeID] {
+		g.incoming[edge.target]--
+		if edge.sourceHandle == "" || edge.sourceHandle == handle {
+			g.active[edge.target]++
+		}
 	}
 }
 
+// hasHandle reports whether any outgoing edge of a node belongs to handle.
+func (g *graph) hasHandle(nodeID string, handle string) bool {
+	for _, edge := range g.edges[nodeID] {
+		if handle != "" && edge.sourceHandle == handle {
+			return true
+		}
+	}
+	return false
+}
+
+// markSkipped marks a node as skipped: its outgoing edges resolve without firing.
+func (g *graph) markSkipped(nodeID string) {
+	for _, edge := range g.edges[nodeID] {
+		g.incoming[edge.target]--
+	}
+}
+
+// shouldSkip reports whether a ready node was reached only through inactive edges.
+func (g *graph) shouldSkip(nodeID string) bool {
+	return g.inDegree[nodeID] > 0 && g.active[nodeID] == 0
+}
+
 // isReady checks if a node is ready to execute (all dependencies met).
 func (g *graph) isReady(nodeID string) bool {
 	return g.incoming[nodeID] == 0
@@ -107,7 +165,8 @@ func (g *graph) hasCycleDFS(nodeID string, visited, recStack map[string]bool) bo
 		visited[nodeID] = true
 		recStack[nodeID] = true
 
-		for _, targetID := range g.edges[nodeID] {
+		for _, edge := range g.edges[nodeID] {
+			targetID := edge.target
 			if !visited[targetID] {
 				if g.hasCycleDFS(targetID, visited, recStack) {
 					return true
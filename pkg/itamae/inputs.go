@@ -0,0 +1,86 @@
+package itamae
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// resolveInputs builds a node's named inputs from its incoming port edges.
+//
+// Each edge with a targetHandle maps a field of the source's output to the
+// input named by the handle. Sources that didn't run (skipped branches)
+// contribute nothing. Returns nil when the node has no port edges.
+func (g *graph) resolveInputs(nodeID string, execCtx *executionContext) (map[string]interface{}, error) {
+	edges := g.inputs[nodeID]
+	if len(edges) == 0 {
+		return nil, nil
+	}
+
+	inputs := make(map[string]interface{}, len(edges))
+	for _, edge := range edges {
+		output, ok := execCtx.get(edge.Source)
+		if !ok {
+			continue
+		}
+
+		field := sourceField(g.nodes[edge.Source], edge.SourceHandle)
+		value, ok := lookupField(output, field)
+		if !ok {
+			return nil, fmt.Errorf("input '%s': output of '%s' has no field '%s'", edge.TargetHandle, edge.Source, field)
+		}
+		inputs[edge.TargetHandle] = value
+	}
+	return inputs, nil
+}
+
+// sourceField returns the output field an edge's sourceHandle selects.
+//
+// A handle naming a declared output port selects the port's field (the whole
+// output when the port has none). Other handles name a field directly.
+// Branch handles route execution and always pass the whole output.
+func sourceField(source *neta.Definition, handle string) string {
+	if handle == "" || branchTypes[source.Type] {
+		return ""
+	}
+	for _, port := range source.OutputPorts {
+		if port.ID == handle {
+			return port.Field
+		}
+	}
+	return handle
+}
+
+// lookupField returns the value at a dot-separated path in a node output.
+// An empty path returns the whole output.
+func lookupField(output interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return output, true
+	}
+
+	current := output
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// withGraphInputs returns the context a graph node runs with: execCtx with
+// the node's resolved inputs attached, or execCtx itself when it has none.
+func (i *Itamae) withGraphInputs(g *graph, node *neta.Definition, execCtx *executionContext) (*executionContext, error) {
+	inputs, err := g.resolveInputs(node.ID, execCtx)
+	if err != nil {
+		return nil, newNodeError(node.ID, node.Type, "resolve inputs", err)
+	}
+	if inputs == nil {
+		return execCtx, nil
+	}
+	return execCtx.withInputs(inputs), nil
+}
@@ -0,0 +1,96 @@
+package itamae_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// portBento builds a bento where "read" feeds two named inputs of "render".
+func portBento(countHandle string, maxConcurrency int) *neta.Definition {
+	return &neta.Definition{
+		ID:         "port-bento",
+		Type:       "group",
+		Parameters: map[string]interface{}{"maxConcurrency": maxConcurrency},
+		Nodes: []neta.Definition{
+			{
+				ID:          "read",
+				Type:        "source",
+				OutputPorts: []neta.Port{{ID: "out-1", Name: "Rows", Field: "rows"}},
+			},
+			{
+				ID:         "render",
+				Type:       "render",
+				InputPorts: []neta.Port{{ID: "products", Name: "Products"}, {ID: "count", Name: "Count"}},
+			},
+			{ID: "notify", Type: "notify"},
+		},
+		Edges: []neta.Edge{
+			{ID: "e1", Source: "read", Target: "render", SourceHandle: "out-1", TargetHandle: "products"},
+			{ID: "e2", Source: "read", Target: "render", SourceHandle: countHandle, TargetHandle: "count"},
+			{ID: "e3", Source: "render", Target: "notify"},
+		},
+	}
+}
+
+// servePortBento runs portBento and returns the params each node received.
+func servePortBento(countHandle string, maxConcurrency int) (map[string][]map[string]interface{}, error) {
+	calls := make(map[string][]map[string]interface{})
+	sourceCalls := 0
+	p := pantry.New()
+	p.RegisterFactory("source", func() neta.Executable {
+		return &flakyNeta{calls: &sourceCalls, output: map[string]interface{}{
+			"rows": []interface{}{"chair", "table"},
+			"meta": map[string]interface{}{"count": 2},
+		}}
+	})
+	p.RegisterFactory("render", func() neta.Executable { return &recordNeta{nodeType: "render", calls: calls} })
+	p.RegisterFactory("notify", func() neta.Executable { return &recordNeta{nodeType: "notify", calls: calls} })
+
+	_, err := itamae.New(p, nil).Serve(context.Background(), portBento(countHandle, maxConcurrency))
+	return calls, err
+}
+
+// TestItamae_PortInputs tests that port edges deliver output fields as named inputs.
+func TestItamae_PortInputs(t *testing.T) {
+	for _, maxConcurrency := range []int{1, 2} {
+		calls, err := servePortBento("meta.count", maxConcurrency)
+		if err != nil {
+			t.Fatalf("Serve failed (maxConcurrency %d): %v", maxConcurrency, err)
+		}
+
+		inputs, ok := calls["render"][0]["_inputs"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("render should receive _inputs, got %v", calls["render"][0])
+		}
+		products, _ := inputs["products"].([]interface{})
+		if len(products) != 2 || products[0] != "chair" {
+			t.Errorf("products = %v, want the rows output field", inputs["products"])
+		}
+		if inputs["count"] != 2 {
+			t.Errorf("count = %v, want 2", inputs["count"])
+		}
+
+		if _, ok := calls["notify"][0]["_inputs"]; ok {
+			t.Error("node without port edges should not receive _inputs")
+		}
+	}
+}
+
+// TestItamae_PortInputsMissingField tests that a missing source field fails the target.
+func TestItamae_PortInputsMissingField(t *testing.T) {
+	calls, err := servePortBento("meta.total", 1)
+	if err == nil {
+		t.Fatal("Expected error for missing output field")
+	}
+	if !strings.Contains(err.Error(), "meta.total") {
+		t.Errorf("Error should mention the missing field: %v", err)
+	}
+	if len(calls["render"]) != 0 {
+		t.Error("render should not execute without its inputs")
+	}
+}
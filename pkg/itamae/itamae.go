@@ -41,6 +41,16 @@ type ProgressMessenger interface {
 	SendNodeStarted(path, name, nodeType string)
 	SendNodeCompleted(path string, duration time.Duration, err error)
 	SendLoopChild(loopPath, childName string, index, total int)
+	SendNodeSkipped(path, name, nodeType string)
+	SendNodeRetry(path string, attempt, maxAttempts int, err error)
+	SendNodeCached(path string)
+}
+
+// OutputMessenger is implemented by messengers that also want the lines
+// streamed by a running node (e.g. shell-command output).
+// Optional - checked with a type assertion.
+type OutputMessenger interface {
+	SendNodeOutput(path, line string)
 }
 
 // Itamae orchestrates bento execution.
@@ -49,8 +59,18 @@ type Itamae struct {
 	logger      *shoyu.Logger     // Optional - can be nil
 	messenger   ProgressMessenger // Optional - for TUI progress updates
 	onProgress  ProgressCallback
-	slowMoDelay time.Duration   // Delay between node completions for animations
-	state       *executionState // Progress tracking state
+	slowMoDelay time.Duration          // Delay between node completions for animations
+	state       *executionState        // Progress tracking state
+	checkpoint  *Checkpoint            // Optional - persists outputs for resumable runs
+	cache       OutputCache            // Optional - content-addressed output cache
+	loader      BentoLoader            // Optional - loads sub-bentos by name
+	validator   BentoValidator         // Optional - validates sub-bentos before they run
+	bentoDir    string                 // Directory relative sub-bento paths and file patterns resolve against
+	variables   map[string]interface{} // Resolved bento variables, available as {{.NAME}}
+	tracer      Tracer                 // Optional - records timing spans
+	lenient     bool                   // Leave unresolvable templates as they are (strict by default)
+	gracePeriod time.Duration          // Time in-flight nodes get to finish once the run is cancelled
+	resources   *resourcePools         // Named concurrency limits of the current run
 }
 
 // ProgressCallback is called when a node starts/completes execution.
@@ -60,7 +80,11 @@ type ProgressCallback func(nodeID string, status string)
 type Result struct {
 	Status        Status                 // Execution status
 	NodesExecuted int                    // Number of nodes executed
+	NodesRestored int                    // Number of nodes restored from a checkpoint
+	NodesSkipped  int                    // Number of nodes skipped (inactive branches)
+	NodesCached   int                    // Number of nodes served from the output cache
 	NodeOutputs   map[string]interface{} // Output from each node
+	LoopFailures  []IterationFailure     // Failed iterations of loops that continue on error
 	Duration      time.Duration          // Total execution time
 	Error         error                  // Error if execution failed
 }
@@ -101,6 +125,27 @@ func (i *Itamae) SetSlowMoDelay(delay time.Duration) {
 	i.slowMoDelay = delay
 }
 
+// SetVariables sets the bento's variable values for subsequent runs.
+// Values are available to templates as {{.NAME}} and take precedence over
+// environment variables of the same name.
+func (i *Itamae) SetVariables(values map[string]interface{}) {
+	i.variables = values
+}
+
+// SetStrictTemplates sets whether parameter templates that can't be resolved
+// (parse errors, missing keys, failed secrets) fail the node. On by default;
+// when off they are left unresolved, as in earlier versions.
+func (i *Itamae) SetStrictTemplates(strict bool) {
+	i.lenient = !strict
+}
+
+// SetGracePeriod sets how long in-flight nodes may keep running once the
+// context passed to Serve is cancelled. No new node starts in the meantime.
+// With no grace period (the default) in-flight nodes are cancelled at once.
+func (i *Itamae) SetGracePeriod(d time.Duration) {
+	i.gracePeriod = d
+}
+
 // OnProgress registers a callback for progress updates.
 func (i *Itamae) OnProgress(callback ProgressCallback) {
 	i.onProgress = callback
@@ -117,6 +162,7 @@ func (i *Itamae) Serve(ctx context.Context, def *neta.Definition) (*Result, erro
 	// Analyze graph structure and initialize execution state
 	graph := analyzeGraph(def)
 	i.state = newExecutionState(graph)
+	i.resources = newResourcePools(def.Resources)
 
 	if i.logger != nil {
 		msg := msgBentoStarted(def.Name)
@@ -129,19 +175,30 @@ func (i *Itamae) Serve(ctx context.Context, def *neta.Definition) (*Result, erro
 
 	// Create execution context
 	execCtx := newExecutionContext()
+	execCtx.bentoDir = i.bentoDir
+	execCtx.strict = !i.lenient
+	execCtx.setVariables(i.variables)
 
-	// Execute the bento
-	err := i.executeNode(ctx, def, execCtx, result)
+	// Execute the bento (in-flight nodes get the grace period on cancellation)
+	nodeCtx, stop := i.withGracePeriod(ctx)
+	defer stop()
+	err := i.executeNode(nodeCtx, def, execCtx, result)
 
 	result.Duration = time.Since(start)
 
 	if err != nil {
 		result.Status = StatusFailed
+		if runCancelled(nodeCtx) {
+			result.Status = StatusCancelled
+		}
 		result.Error = err
 
 		if i.logger != nil {
 			durationStr := formatDuration(result.Duration)
 			msg := msgBentoFailed(durationStr)
+			if result.Status == StatusCancelled {
+				msg = msgBentoCancelled(durationStr)
+			}
 			i.logger.Error(msg.format())
 			i.logger.Error("Error: " + err.Error())
 		}
@@ -643,3 +643,41 @@ func TestItamae_LoopIterationProgress(t *testing.T) {
 		t.Errorf("Status = %v, want Success", result.Status)
 	}
 }
+
+// TestItamae_StrictTemplates tests that a misspelled reference fails its node
+// unless strict templates are turned off.
+func TestItamae_StrictTemplates(t *testing.T) {
+	def := &neta.Definition{
+		ID:   "strict-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{ID: "read", Type: "render"},
+			{
+				ID:         "render",
+				Type:       "render",
+				Parameters: map[string]interface{}{"values": map[string]interface{}{"rows": "{{.raed.ok}}"}},
+			},
+		},
+		Edges: []neta.Edge{{ID: "e1", Source: "read", Target: "render"}},
+	}
+
+	calls := make(map[string][]map[string]interface{})
+	p := pantry.New()
+	p.RegisterFactory("render", func() neta.Executable { return &recordNeta{nodeType: "render", calls: calls} })
+
+	chef := itamae.New(p, nil)
+	_, err := chef.Serve(context.Background(), def)
+	if err == nil {
+		t.Fatal("Expected error for misspelled reference")
+	}
+	for _, want := range []string{"node 'render'", "parameter 'values.rows'", "{{.raed.ok}}", "no value for 'raed'"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error should contain %q: %v", want, err)
+		}
+	}
+
+	chef.SetStrictTemplates(false)
+	if _, err := chef.Serve(context.Background(), def); err != nil {
+		t.Errorf("Expected lenient run to succeed, got %v", err)
+	}
+}
@@ -37,9 +37,12 @@ func (i *Itamae) executeLoop(
 		return err
 	}
 
+	// Children run without results of their own, so the loop counts their cache hits
+	ctx, cacheHits := withCacheHits(ctx)
+
 	var err error
 	switch mode {
-	case "forEach":
+	case "forEach", "files":
 		err = i.executeForEach(ctx, def, execCtx, result)
 	case "times":
 		err = i.executeTimes(ctx, def, execCtx, result)
@@ -50,6 +53,7 @@ func (i *Itamae) executeLoop(
 			fmt.Errorf("unknown loop mode: %s", mode))
 	}
 
+	result.NodesCached += int(cacheHits.Load())
 	duration := time.Since(start)
 
 	// Send messenger event: node completed
@@ -59,15 +63,3 @@ func (i *Itamae) executeLoop(
 
 	return err
 }
-
-// executeWhile executes a while loop.
-func (i *Itamae) executeWhile(
-	ctx context.Context,
-	def *neta.Definition,
-	execCtx *executionContext,
-	result *Result,
-) error {
-	// While loop implementation (stub for now)
-	return newNodeError(def.ID, "loop", "execute",
-		fmt.Errorf("while loops not yet implemented"))
-}
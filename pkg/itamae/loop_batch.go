@@ -0,0 +1,108 @@
+package itamae
+
+import (
+	"fmt"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// isBatchLoop reports whether a forEach or files loop hands its children
+// batches of items (batchSize parameter) instead of single items.
+func isBatchLoop(def *neta.Definition) bool {
+	_, ok := def.Parameters["batchSize"]
+	mode := def.Parameters["mode"]
+	return ok && (mode == "forEach" || mode == "files")
+}
+
+// getLoopBatchSize extracts the batchSize parameter (0 = no batching).
+func getLoopBatchSize(def *neta.Definition) (int, error) {
+	if !isBatchLoop(def) {
+		return 0, nil
+	}
+
+	var size int
+	switch v := def.Parameters["batchSize"].(type) {
+	case int:
+		size = v
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("'batchSize' must be a whole number, got %v", v)
+		}
+		size = int(v)
+	default:
+		return 0, fmt.Errorf("'batchSize' must be a number, got %T", v)
+	}
+	if size < 1 {
+		return 0, fmt.Errorf("'batchSize' must be at least 1, got %d", size)
+	}
+	return size, nil
+}
+
+// batchItems splits items into consecutive batches of at most size items.
+func batchItems(items []interface{}, size int) []interface{} {
+	batches := make([]interface{}, 0, (len(items)+size-1)/size)
+	for start := 0; start < len(items); start += size {
+		end := min(start+size, len(items))
+		batches = append(batches, items[start:end])
+	}
+	return batches
+}
+
+// setIterationVars exposes the current item to an iteration's children:
+// item and index, or batch, batchIndex and batchCount for batch loops.
+func setIterationVars(def *neta.Definition, iterCtx *executionContext, item interface{}, idx, total int) {
+	if isBatchLoop(def) {
+		iterCtx.set("batch", item)
+		iterCtx.set("batchIndex", idx)
+		iterCtx.set("batchCount", total)
+		return
+	}
+	iterCtx.set("item", item)
+	iterCtx.set("index", idx)
+}
+
+// flattenBatchResults turns per-batch results back into per-item results
+// when the loop sets flatten.
+//
+// flatten: true takes each batch's last child output; a string selects a
+// field of the batch result instead ("upload.body.results"). The selected
+// value must be a list. Failed batches (continueOnError) contribute one nil
+// per item.
+func flattenBatchResults(def *neta.Definition, batches, results []interface{}) ([]interface{}, error) {
+	path, ok := flattenPath(def)
+	if !ok {
+		return results, nil
+	}
+
+	var flattened []interface{}
+	for idx, result := range results {
+		batch := batches[idx].([]interface{})
+		iterResult, ok := result.(map[string]interface{})
+		if !ok {
+			flattened = append(flattened, make([]interface{}, len(batch))...)
+			continue
+		}
+
+		value, found := lookupField(iterResult, path)
+		list, isList := value.([]interface{})
+		if !found || !isList {
+			return nil, newNodeError(def.ID, "loop", "flatten",
+				fmt.Errorf("batch %d: '%s' is not a list (got %T)", idx, path, value))
+		}
+		flattened = append(flattened, list...)
+	}
+	return flattened, nil
+}
+
+// flattenPath returns the batch result field that flatten selects.
+func flattenPath(def *neta.Definition) (string, bool) {
+	switch v := def.Parameters["flatten"].(type) {
+	case bool:
+		if v && len(def.Nodes) > 0 {
+			return def.Nodes[len(def.Nodes)-1].ID, true
+		}
+	case string:
+		return v, v != ""
+	}
+	return "", false
+}
@@ -0,0 +1,129 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// bulkNeta handles a batch of SKUs in one call, like a bulk API endpoint.
+type bulkNeta struct {
+	mu    *sync.Mutex
+	calls *[]map[string]interface{}
+}
+
+func (b *bulkNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	*b.calls = append(*b.calls, params)
+	b.mu.Unlock()
+
+	skus, _ := params["skus"].([]interface{})
+	results := make([]interface{}, len(skus))
+	for idx, sku := range skus {
+		results[idx] = fmt.Sprintf("uploaded %v", sku)
+	}
+	return map[string]interface{}{"results": results}, nil
+}
+
+// serveBatchLoop runs a forEach loop over five SKUs with extra loop parameters.
+func serveBatchLoop(t *testing.T, params map[string]interface{}) (*itamae.Result, []map[string]interface{}, error) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls []map[string]interface{}
+	p := pantry.New()
+	p.RegisterFactory("bulk", func() neta.Executable { return &bulkNeta{mu: &mu, calls: &calls} })
+
+	loopParams := map[string]interface{}{
+		"mode":  "forEach",
+		"items": []interface{}{"A", "B", "C", "D", "E"},
+	}
+	for k, v := range params {
+		loopParams[k] = v
+	}
+
+	result, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:         "upload-all",
+		Type:       "loop",
+		Parameters: loopParams,
+		Nodes: []neta.Definition{{
+			ID:   "upload",
+			Type: "bulk",
+			Parameters: map[string]interface{}{
+				"skus":  "{{.batch}}",
+				"batch": "{{.batchIndex}}/{{.batchCount}}",
+			},
+		}},
+	})
+	return result, calls, err
+}
+
+// TestItamae_LoopBatches tests that children receive batches of items,
+// sequentially and with maxConcurrency.
+func TestItamae_LoopBatches(t *testing.T) {
+	for _, maxConcurrency := range []float64{1, 2} {
+		result, calls, err := serveBatchLoop(t, map[string]interface{}{
+			"batchSize":      float64(2),
+			"maxConcurrency": maxConcurrency,
+		})
+		if err != nil {
+			t.Fatalf("maxConcurrency %v: Serve failed: %v", maxConcurrency, err)
+		}
+
+		batches := make(map[string]interface{})
+		for _, call := range calls {
+			batches[call["batch"].(string)] = call["skus"]
+		}
+		want := map[string]interface{}{
+			"0/3": []interface{}{"A", "B"},
+			"1/3": []interface{}{"C", "D"},
+			"2/3": []interface{}{"E"},
+		}
+		if !reflect.DeepEqual(batches, want) {
+			t.Errorf("maxConcurrency %v: batches = %v, want %v", maxConcurrency, batches, want)
+		}
+
+		if output := result.NodeOutputs["upload-all"].([]interface{}); len(output) != 3 {
+			t.Errorf("maxConcurrency %v: loop output has %d entries, want one per batch", maxConcurrency, len(output))
+		}
+	}
+}
+
+// TestItamae_LoopBatchesFlatten tests that batch results are flattened back
+// into per-item results.
+func TestItamae_LoopBatchesFlatten(t *testing.T) {
+	result, _, err := serveBatchLoop(t, map[string]interface{}{
+		"batchSize":      float64(2),
+		"maxConcurrency": float64(2),
+		"flatten":        "upload.results",
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	want := []interface{}{"uploaded A", "uploaded B", "uploaded C", "uploaded D", "uploaded E"}
+	if got := result.NodeOutputs["upload-all"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("loop output = %v, want %v", got, want)
+	}
+}
+
+// TestItamae_LoopBatchesInvalid tests batchSize and flatten validation.
+func TestItamae_LoopBatchesInvalid(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"batchSize": float64(0)},
+		{"batchSize": "50"},
+		{"batchSize": float64(2), "flatten": true}, // upload returns a map
+	}
+
+	for _, params := range tests {
+		if _, _, err := serveBatchLoop(t, params); err == nil {
+			t.Errorf("Expected error for %v", params)
+		}
+	}
+}
@@ -20,7 +20,10 @@ func (i *Itamae) extractLoopItems(
 			"itemsParam_type", fmt.Sprintf("%T", itemsParam))
 	}
 
-	resolved := execCtx.resolveValue(itemsParam)
+	resolved, err := execCtx.resolveParam("items", itemsParam)
+	if err != nil {
+		return nil, newNodeError(def.ID, "loop", "resolve params", err)
+	}
 
 	if i.logger != nil {
 		i.logger.Debug("Loop items resolved",
@@ -0,0 +1,117 @@
+package itamae
+
+import (
+	"fmt"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// IterationFailure describes a failed iteration of a loop that continues on
+// error. Results list them so callers can report and rerun the failed items.
+type IterationFailure struct {
+	LoopID string      // ID of the loop
+	Index  int         // Iteration index (batch index for batch loops)
+	Item   interface{} // The iteration's item (its batch for batch loops)
+	NodeID string      // Child node that failed
+	Error  string      // Failure message
+}
+
+// loopFailures collects the failed iterations of a loop.
+//
+// A loop tolerates failures when it sets continueOnError, maxFailures,
+// failureThreshold or failureReport. With any but continueOnError its output
+// reports the failures next to the results; with continueOnError alone it
+// stays a list of results with nil for failed iterations, as before.
+type loopFailures struct {
+	tolerant    bool
+	report      bool    // Output the failures and counts next to the results
+	maxFailures int     // Most failures tolerated (-1 = unlimited)
+	threshold   float64 // Largest tolerated fraction of failed iterations (0 = none)
+	total       int
+	list        []IterationFailure
+}
+
+// newLoopFailures reads a loop's failure policy for total iterations.
+func newLoopFailures(def *neta.Definition, total int) (*loopFailures, error) {
+	f := &loopFailures{maxFailures: -1, total: total}
+	f.tolerant, _ = def.Parameters["continueOnError"].(bool)
+
+	if v, ok := def.Parameters["failureReport"]; ok {
+		report, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'failureReport' must be a boolean, got %v", v)
+		}
+		f.report = report
+		f.tolerant = f.tolerant || report
+	}
+
+	if v, ok := def.Parameters["maxFailures"]; ok {
+		n, ok := v.(float64)
+		if iv, isInt := v.(int); isInt {
+			n, ok = float64(iv), true
+		}
+		if !ok || n < 0 || n != float64(int(n)) {
+			return nil, fmt.Errorf("'maxFailures' must be a non-negative whole number, got %v", v)
+		}
+		f.maxFailures = int(n)
+		f.tolerant = true
+		f.report = true
+	}
+
+	if v, ok := def.Parameters["failureThreshold"]; ok {
+		n, ok := v.(float64)
+		if !ok || n <= 0 || n > 1 {
+			return nil, fmt.Errorf("'failureThreshold' must be a fraction between 0 and 1, got %v", v)
+		}
+		f.threshold = n
+		f.tolerant = true
+		f.report = true
+	}
+	return f, nil
+}
+
+// record adds a failed iteration. Returns an error once the loop has more
+// failures than its policy tolerates.
+func (f *loopFailures) record(def *neta.Definition, idx int, item interface{}, err error) error {
+	caught := caughtError(err)
+	f.list = append(f.list, IterationFailure{
+		LoopID: def.ID,
+		Index:  idx,
+		Item:   item,
+		NodeID: caught["nodeId"].(string),
+		Error:  caught["message"].(string),
+	})
+
+	failed := len(f.list)
+	if f.maxFailures >= 0 && failed > f.maxFailures {
+		return fmt.Errorf("%d of %d iterations failed (maxFailures %d): %w", failed, f.total, f.maxFailures, err)
+	}
+	if f.threshold > 0 && float64(failed) > f.threshold*float64(f.total) {
+		return fmt.Errorf("%d of %d iterations failed (failureThreshold %g): %w", failed, f.total, f.threshold, err)
+	}
+	return nil
+}
+
+// output returns the loop's output: the results alone, or for loops that
+// report failures the results with the failures and counts.
+func (f *loopFailures) output(results []interface{}) interface{} {
+	if !f.report {
+		return results
+	}
+
+	failures := make([]interface{}, len(f.list))
+	for idx, failure := range f.list {
+		failures[idx] = map[string]interface{}{
+			"index":  failure.Index,
+			"item":   failure.Item,
+			"nodeId": failure.NodeID,
+			"error":  failure.Error,
+		}
+	}
+	return map[string]interface{}{
+		"results":   results,
+		"failures":  failures,
+		"succeeded": f.total - len(f.list),
+		"failed":    len(f.list),
+	}
+}
@@ -0,0 +1,193 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// skuNeta fails for the SKUs B and D.
+type skuNeta struct{}
+
+func (s *skuNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if sku := params["sku"]; sku == "B" || sku == "D" {
+		return nil, fmt.Errorf("no model for %v", sku)
+	}
+	return map[string]interface{}{"sku": params["sku"]}, nil
+}
+
+// serveFailingLoop runs a forEach loop over five SKUs, two of which fail.
+func serveFailingLoop(params map[string]interface{}) (*itamae.Result, error) {
+	p := pantry.New()
+	p.RegisterFactory("sku", func() neta.Executable { return &skuNeta{} })
+
+	loopParams := map[string]interface{}{
+		"mode":  "forEach",
+		"items": []interface{}{"A", "B", "C", "D", "E"},
+	}
+	for k, v := range params {
+		loopParams[k] = v
+	}
+
+	return itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:         "render-all",
+		Type:       "loop",
+		Parameters: loopParams,
+		Nodes: []neta.Definition{
+			{ID: "render", Type: "sku", Parameters: map[string]interface{}{"sku": "{{.item}}"}},
+		},
+	})
+}
+
+// TestItamae_LoopFailureReport tests that a loop with failureReport reports
+// its failed iterations, sequentially and with maxConcurrency.
+func TestItamae_LoopFailureReport(t *testing.T) {
+	for _, maxConcurrency := range []float64{1, 3} {
+		result, err := serveFailingLoop(map[string]interface{}{
+			"failureReport":  true,
+			"maxConcurrency": maxConcurrency,
+		})
+		if err != nil {
+			t.Fatalf("maxConcurrency %v: Serve failed: %v", maxConcurrency, err)
+		}
+
+		output := result.NodeOutputs["render-all"].(map[string]interface{})
+		if output["succeeded"] != 3 || output["failed"] != 2 {
+			t.Errorf("maxConcurrency %v: succeeded/failed = %v/%v, want 3/2",
+				maxConcurrency, output["succeeded"], output["failed"])
+		}
+		if results := output["results"].([]interface{}); len(results) != 5 || results[1] != nil {
+			t.Errorf("maxConcurrency %v: results = %v, want 5 with nil for failures", maxConcurrency, results)
+		}
+
+		failures := output["failures"].([]interface{})
+		sort.Slice(failures, func(a, b int) bool {
+			return failures[a].(map[string]interface{})["index"].(int) < failures[b].(map[string]interface{})["index"].(int)
+		})
+		first := failures[0].(map[string]interface{})
+		if first["index"] != 1 || first["item"] != "B" || first["nodeId"] != "render" || first["error"] != "no model for B" {
+			t.Errorf("maxConcurrency %v: first failure = %v", maxConcurrency, first)
+		}
+
+		if len(result.LoopFailures) != 2 || result.LoopFailures[0].LoopID != "render-all" {
+			t.Errorf("maxConcurrency %v: LoopFailures = %+v, want 2 for render-all", maxConcurrency, result.LoopFailures)
+		}
+	}
+}
+
+// TestItamae_LoopContinueOnError tests that a loop only continuing on error
+// keeps its list output, so existing templates over it still work.
+func TestItamae_LoopContinueOnError(t *testing.T) {
+	p := pantry.New()
+	p.RegisterFactory("sku", func() neta.Executable { return &skuNeta{} })
+	calls := make(map[string][]map[string]interface{})
+	p.RegisterFactory("report", func() neta.Executable { return &recordNeta{nodeType: "report", calls: calls} })
+
+	result, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:   "renders-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{
+				ID:   "renders",
+				Type: "loop",
+				Parameters: map[string]interface{}{
+					"mode":            "forEach",
+					"items":           []interface{}{"A", "B", "C"},
+					"continueOnError": true,
+				},
+				Nodes: []neta.Definition{
+					{ID: "render", Type: "sku", Parameters: map[string]interface{}{"sku": "{{.item}}"}},
+				},
+			},
+			{ID: "report", Type: "report", Parameters: map[string]interface{}{
+				"first": "{{(index .renders 0).render.sku}}",
+				"count": "{{len .renders}}",
+			}},
+		},
+		Edges: []neta.Edge{{ID: "e1", Source: "renders", Target: "report"}},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	results, ok := result.NodeOutputs["renders"].([]interface{})
+	if !ok || len(results) != 3 || results[1] != nil {
+		t.Errorf("renders output = %v, want 3 results with nil for B", result.NodeOutputs["renders"])
+	}
+	if report := calls["report"][0]; report["first"] != "A" || report["count"] != "3" {
+		t.Errorf("report params = %v, want first A and count 3", report)
+	}
+	if len(result.LoopFailures) != 1 {
+		t.Errorf("LoopFailures = %+v, want B", result.LoopFailures)
+	}
+}
+
+// TestItamae_LoopFailureLimits tests that maxFailures and failureThreshold
+// fail the loop after too many failed iterations.
+func TestItamae_LoopFailureLimits(t *testing.T) {
+	tests := []struct {
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"maxFailures": float64(2)}, ""},
+		{map[string]interface{}{"maxFailures": float64(1)}, "2 of 5 iterations failed (maxFailures 1)"},
+		{map[string]interface{}{"failureThreshold": 0.4}, ""},
+		{map[string]interface{}{"failureThreshold": 0.3}, "2 of 5 iterations failed (failureThreshold 0.3)"},
+		{map[string]interface{}{"maxFailures": float64(-1)}, "'maxFailures' must be"},
+		{map[string]interface{}{"failureThreshold": float64(2)}, "'failureThreshold' must be"},
+	}
+
+	for _, tt := range tests {
+		_, err := serveFailingLoop(tt.params)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%v: Serve failed: %v", tt.params, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%v: error = %v, want %q", tt.params, err, tt.wantErr)
+		}
+	}
+}
+
+// TestItamae_LoopFailureLimitStops tests that reaching the failure limit of a
+// concurrent loop cancels the iterations in flight and starts no others.
+func TestItamae_LoopFailureLimitStops(t *testing.T) {
+	cancelled := make(chan struct{}, 4)
+	chef := itamae.New(newSleepyPantry(cancelled), nil)
+
+	start := time.Now()
+	_, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "download-all",
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode": "forEach",
+			"items": []interface{}{
+				map[string]interface{}{"delay": "10ms", "fail": true},
+				map[string]interface{}{"delay": "5s", "fail": false},
+				map[string]interface{}{"delay": "5s", "fail": false},
+				map[string]interface{}{"delay": "5s", "fail": false},
+			},
+			"maxConcurrency": float64(2),
+			"maxFailures":    float64(0),
+		},
+		Nodes: []neta.Definition{{ID: "download", Type: "sleepy", Parameters: map[string]interface{}{
+			"delay": "{{.item.delay}}",
+			"fail":  "{{.item.fail}}",
+		}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "(maxFailures 0)") {
+		t.Fatalf("error = %v, want the maxFailures error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %v, remaining iterations kept running", elapsed)
+	}
+	if len(cancelled) != 1 {
+		t.Errorf("%d iterations cancelled, want only the one in flight", len(cancelled))
+	}
+}
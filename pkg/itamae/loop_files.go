@@ -0,0 +1,180 @@
+package itamae
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// fileLoopOptions are the parameters of a files loop.
+type fileLoopOptions struct {
+	pattern    string    // Glob pattern, "**" matches any number of directories
+	only       string    // "files", "dirs" or "" for both
+	newerThan  time.Time // Zero time = no age filter
+	sortBy     string    // "path", "name", "size" or "mtime"
+	descending bool
+}
+
+// extractFileItems returns the items of a files loop: one map per path
+// matching its pattern, with path, name, ext, dir, size, mtime and isDir.
+// A relative pattern is relative to the bento's directory, like sub-bento paths.
+func (i *Itamae) extractFileItems(def *neta.Definition, execCtx *executionContext) ([]interface{}, error) {
+	opts, err := parseFileLoopOptions(def, execCtx)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(opts.pattern) && execCtx.bentoDir != "" {
+		opts.pattern = filepath.Join(execCtx.bentoDir, opts.pattern)
+	}
+
+	matches, err := globFiles(opts.pattern)
+	if err != nil {
+		return nil, newNodeError(def.ID, "loop", "glob", err)
+	}
+
+	matches = filterFileMatches(matches, opts)
+	sortFileMatches(matches, opts)
+
+	items := make([]interface{}, len(matches))
+	for idx, m := range matches {
+		items[idx] = fileItem(m)
+	}
+	return items, nil
+}
+
+// parseFileLoopOptions resolves and validates the parameters of a files loop.
+func parseFileLoopOptions(def *neta.Definition, execCtx *executionContext) (fileLoopOptions, error) {
+	opts := fileLoopOptions{sortBy: "path"}
+
+	pattern, err := execCtx.resolveParam("pattern", def.Parameters["pattern"])
+	if err != nil {
+		return opts, newNodeError(def.ID, "loop", "resolve params", err)
+	}
+	newerThan, err := execCtx.resolveParam("newerThan", def.Parameters["newerThan"])
+	if err != nil {
+		return opts, newNodeError(def.ID, "loop", "resolve params", err)
+	}
+
+	if err := opts.set(pattern, newerThan, def.Parameters); err != nil {
+		return opts, newNodeError(def.ID, "loop", "validate", err)
+	}
+	return opts, nil
+}
+
+// set fills in the options from the loop's (resolved) parameters.
+func (o *fileLoopOptions) set(pattern, newerThan interface{}, params map[string]interface{}) error {
+	var ok bool
+	if o.pattern, ok = pattern.(string); !ok || o.pattern == "" {
+		return fmt.Errorf("'pattern' must be a non-empty string, got %v", pattern)
+	}
+
+	if only, ok := params["only"]; ok {
+		if only != "files" && only != "dirs" {
+			return fmt.Errorf("'only' must be \"files\" or \"dirs\", got %v", only)
+		}
+		o.only = only.(string)
+	}
+
+	if newerThan != nil {
+		t, err := parseNewerThan(newerThan)
+		if err != nil {
+			return err
+		}
+		o.newerThan = t
+	}
+
+	if sortBy, ok := params["sortBy"]; ok {
+		switch sortBy {
+		case "path", "name", "size", "mtime":
+			o.sortBy = sortBy.(string)
+		default:
+			return fmt.Errorf("'sortBy' must be path, name, size or mtime, got %v", sortBy)
+		}
+	}
+
+	if order, ok := params["order"]; ok {
+		if order != "asc" && order != "desc" {
+			return fmt.Errorf("'order' must be \"asc\" or \"desc\", got %v", order)
+		}
+		o.descending = order == "desc"
+	}
+	return nil
+}
+
+// parseNewerThan accepts a duration ("24h", meaning that long ago),
+// an RFC 3339 time or a date ("2006-01-02").
+func parseNewerThan(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("'newerThan' must be a duration, time or date, got %T", v)
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("'newerThan' must be a duration (\"24h\"), RFC 3339 time or date (\"2006-01-02\"), got %q", s)
+}
+
+// filterFileMatches keeps the matches of the requested kind and age.
+func filterFileMatches(matches []fileMatch, opts fileLoopOptions) []fileMatch {
+	kept := matches[:0]
+	for _, m := range matches {
+		if opts.only == "files" && m.info.IsDir() || opts.only == "dirs" && !m.info.IsDir() {
+			continue
+		}
+		if !opts.newerThan.IsZero() && !m.info.ModTime().After(opts.newerThan) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// sortFileMatches orders matches by the sortBy field, ties broken by path.
+func sortFileMatches(matches []fileMatch, opts fileLoopOptions) {
+	less := func(a, b fileMatch) bool {
+		switch opts.sortBy {
+		case "name":
+			if a.info.Name() != b.info.Name() {
+				return a.info.Name() < b.info.Name()
+			}
+		case "size":
+			if a.info.Size() != b.info.Size() {
+				return a.info.Size() < b.info.Size()
+			}
+		case "mtime":
+			if !a.info.ModTime().Equal(b.info.ModTime()) {
+				return a.info.ModTime().Before(b.info.ModTime())
+			}
+		}
+		return a.path < b.path
+	}
+
+	sort.SliceStable(matches, func(x, y int) bool {
+		if opts.descending {
+			return less(matches[y], matches[x])
+		}
+		return less(matches[x], matches[y])
+	})
+}
+
+// fileItem describes a match as a loop item.
+func fileItem(m fileMatch) map[string]interface{} {
+	return map[string]interface{}{
+		"path":  m.path,
+		"name":  m.info.Name(),
+		"ext":   filepath.Ext(m.path),
+		"dir":   filepath.Dir(m.path),
+		"size":  m.info.Size(),
+		"mtime": m.info.ModTime().Format(time.RFC3339),
+		"isDir": m.info.IsDir(),
+	}
+}
@@ -0,0 +1,159 @@
+package itamae
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/neta/library/filesystem"
+)
+
+// fileMatch is a path matched by a files loop pattern.
+type fileMatch struct {
+	path string
+	info fs.FileInfo
+}
+
+// globFiles returns the files and directories matching pattern, in walk order.
+// A "**" segment matches any number of directories (including none).
+// Paths ignored by the .bentoignore in the pattern's base directory - the
+// part before the first wildcard - are left out, along with anything below them.
+// A base directory that doesn't exist (yet) matches nothing, as with filepath.Glob.
+func globFiles(pattern string) ([]fileMatch, error) {
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	if err := validateGlob(segments); err != nil {
+		return nil, err
+	}
+
+	k := staticPrefixLen(segments)
+	base := filepath.FromSlash(strings.Join(segments[:k], "/"))
+	if base == "" {
+		base = string(filepath.Separator) // pattern starting at the root
+		if k == 0 {
+			base = "."
+		}
+	}
+	if k == len(segments) {
+		return statMatch(base)
+	}
+	if _, err := os.Stat(base); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	ignore, err := filesystem.LoadBentoIgnore(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .bentoignore: %w", err)
+	}
+	return walkGlob(base, segments[k:], ignore)
+}
+
+// validateGlob reports malformed segments (e.g. an unclosed "[").
+func validateGlob(segments []string) error {
+	for _, seg := range segments {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("invalid pattern segment %q: %w", seg, err)
+		}
+	}
+	return nil
+}
+
+// staticPrefixLen returns how many leading segments contain no wildcards.
+func staticPrefixLen(segments []string) int {
+	for k, seg := range segments {
+		if strings.ContainsAny(seg, "*?[") {
+			return k
+		}
+	}
+	return len(segments)
+}
+
+// statMatch handles a pattern without wildcards: the path itself, if it exists.
+func statMatch(p string) ([]fileMatch, error) {
+	info, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []fileMatch{{path: p, info: info}}, nil
+}
+
+// walkGlob walks base and collects the paths whose path relative to base
+// matches the remaining pattern segments.
+func walkGlob(base string, pattern []string, ignore *filesystem.BentoIgnore) ([]fileMatch, error) {
+	recursive := false
+	for _, seg := range pattern {
+		recursive = recursive || seg == "**"
+	}
+
+	var matches []fileMatch
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == base {
+			return nil
+		}
+
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		if ignore.ShouldIgnore(rel) {
+			return skipEntry(d)
+		}
+
+		relSegments := strings.Split(filepath.ToSlash(rel), "/")
+		if matchSegments(pattern, relSegments) {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			matches = append(matches, fileMatch{path: p, info: info})
+		}
+
+		// Without "**" nothing deeper than the pattern can match
+		if !recursive && len(relSegments) >= len(pattern) {
+			return skipEntry(d)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// skipEntry skips a directory's contents during a walk (files need no skipping).
+func skipEntry(d fs.DirEntry) error {
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// matchSegments reports whether the path segments match the pattern
+// segments, where "**" matches zero or more segments.
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for j := 0; j <= len(segments); j++ {
+				if matchSegments(pattern[1:], segments[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,266 @@
+package itamae_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// echoNeta returns its parameters as its output.
+type echoNeta struct{}
+
+func (e *echoNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return params, nil
+}
+
+// productTree creates a products folder for files loops:
+//
+//	products/.bentoignore   (ignores "archive")
+//	products/a/model.stl
+//	products/b/model.stl
+//	products/b/notes.txt    (modified two days ago)
+//	products/c/x/model.stl
+//	products/archive/model.stl
+func productTree(t *testing.T) string {
+	t.Helper()
+
+	root := filepath.Join(t.TempDir(), "products")
+	files := map[string]string{
+		".bentoignore":      "# old products\narchive\n",
+		"a/model.stl":       "solid a",
+		"b/model.stl":       "solid bb",
+		"b/notes.txt":       "notes",
+		"c/x/model.stl":     "solid c",
+		"archive/model.stl": "solid old",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(root, "b/notes.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// serveFilesLoop runs a files loop whose child echoes each item's path,
+// and returns the paths relative to root in iteration order.
+func serveFilesLoop(root string, params map[string]interface{}) ([]string, error) {
+	p := pantry.New()
+	p.RegisterFactory("echo", func() neta.Executable { return &echoNeta{} })
+
+	loopParams := map[string]interface{}{"mode": "files"}
+	for k, v := range params {
+		loopParams[k] = v
+	}
+
+	result, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:         "models",
+		Type:       "loop",
+		Parameters: loopParams,
+		Nodes: []neta.Definition{{
+			ID:         "visit",
+			Type:       "echo",
+			Parameters: map[string]interface{}{"path": "{{.item.path}}"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, iteration := range result.NodeOutputs["models"].([]interface{}) {
+		visit := iteration.(map[string]interface{})["visit"].(map[string]interface{})
+		rel, _ := filepath.Rel(root, visit["path"].(string))
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths, nil
+}
+
+// TestItamae_FilesLoop tests glob patterns, .bentoignore and the filters.
+func TestItamae_FilesLoop(t *testing.T) {
+	root := productTree(t)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   []string
+	}{
+		{
+			name:   "double star",
+			params: map[string]interface{}{"pattern": root + "/**/model.stl"},
+			want:   []string{"a/model.stl", "b/model.stl", "c/x/model.stl"},
+		},
+		{
+			name:   "single level",
+			params: map[string]interface{}{"pattern": root + "/*/model.stl"},
+			want:   []string{"a/model.stl", "b/model.stl"},
+		},
+		{
+			name:   "only dirs, descending",
+			params: map[string]interface{}{"pattern": root + "/*", "only": "dirs", "order": "desc"},
+			want:   []string{"c", "b", "a"},
+		},
+		{
+			name:   "only files",
+			params: map[string]interface{}{"pattern": root + "/b/*", "only": "files"},
+			want:   []string{"b/model.stl", "b/notes.txt"},
+		},
+		{
+			name:   "newer than",
+			params: map[string]interface{}{"pattern": root + "/b/*", "newerThan": "24h"},
+			want:   []string{"b/model.stl"},
+		},
+		{
+			name:   "sort by size",
+			params: map[string]interface{}{"pattern": root + "/**/*.stl", "sortBy": "size", "order": "desc"},
+			want:   []string{"b/model.stl", "c/x/model.stl", "a/model.stl"},
+		},
+		{
+			name:   "no matches",
+			params: map[string]interface{}{"pattern": root + "/**/*.blend"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := serveFilesLoop(root, tt.params)
+			if err != nil {
+				t.Fatalf("Serve failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestItamae_FilesLoopItem tests the fields of a files loop item.
+func TestItamae_FilesLoopItem(t *testing.T) {
+	root := productTree(t)
+	p := pantry.New()
+	p.RegisterFactory("echo", func() neta.Executable { return &echoNeta{} })
+
+	result, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:   "models",
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode":    "files",
+			"pattern": root + "/a/*.stl",
+		},
+		Nodes: []neta.Definition{{
+			ID:         "visit",
+			Type:       "echo",
+			Parameters: map[string]interface{}{"item": "{{.item}}"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	iteration := result.NodeOutputs["models"].([]interface{})[0].(map[string]interface{})
+	item := iteration["visit"].(map[string]interface{})["item"].(map[string]interface{})
+
+	want := map[string]interface{}{
+		"path":  filepath.Join(root, "a", "model.stl"),
+		"name":  "model.stl",
+		"ext":   ".stl",
+		"dir":   filepath.Join(root, "a"),
+		"size":  int64(len("solid a")),
+		"isDir": false,
+	}
+	for key, value := range want {
+		if item[key] != value {
+			t.Errorf("item[%q] = %v, want %v", key, item[key], value)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339, item["mtime"].(string)); err != nil {
+		t.Errorf("mtime %v is not an RFC 3339 time: %v", item["mtime"], err)
+	}
+}
+
+// TestItamae_FilesLoopBentoDir tests that relative patterns match in the
+// bento's directory rather than the working directory.
+func TestItamae_FilesLoopBentoDir(t *testing.T) {
+	root := productTree(t)
+	p := pantry.New()
+	p.RegisterFactory("echo", func() neta.Executable { return &echoNeta{} })
+
+	chef := itamae.New(p, nil)
+	chef.SetBentoDir(filepath.Dir(root))
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:         "models",
+		Type:       "loop",
+		Parameters: map[string]interface{}{"mode": "files", "pattern": "products/*/model.stl"},
+		Nodes: []neta.Definition{{
+			ID:         "visit",
+			Type:       "echo",
+			Parameters: map[string]interface{}{"path": "{{.item.path}}"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	iterations := result.NodeOutputs["models"].([]interface{})
+	if len(iterations) != 2 {
+		t.Fatalf("%d iterations, want a and b", len(iterations))
+	}
+	visit := iterations[0].(map[string]interface{})["visit"].(map[string]interface{})
+	if want := filepath.Join(root, "a", "model.stl"); visit["path"] != want {
+		t.Errorf("path = %v, want %v", visit["path"], want)
+	}
+}
+
+// TestItamae_FilesLoopMissingBase tests that a pattern whose base directory
+// doesn't exist yet matches nothing instead of failing the loop.
+func TestItamae_FilesLoopMissingBase(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "renders")
+
+	paths, err := serveFilesLoop(root, map[string]interface{}{"pattern": root + "/**/*.png"})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("paths = %v, want none", paths)
+	}
+}
+
+// TestItamae_FilesLoopInvalidOptions tests that bad options fail the loop.
+func TestItamae_FilesLoopInvalidOptions(t *testing.T) {
+	root := productTree(t)
+
+	tests := []struct {
+		params  map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{}, "'pattern'"},
+		{map[string]interface{}{"pattern": root + "/[a"}, "invalid pattern"},
+		{map[string]interface{}{"pattern": root + "/*", "only": "links"}, "'only'"},
+		{map[string]interface{}{"pattern": root + "/*", "sortBy": "color"}, "'sortBy'"},
+		{map[string]interface{}{"pattern": root + "/*", "newerThan": "last week"}, "'newerThan'"},
+	}
+
+	for _, tt := range tests {
+		_, err := serveFilesLoop(root, tt.params)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("params %v: error = %v, want mention of %s", tt.params, err, tt.wantErr)
+		}
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/Develonaut/bento/pkg/neta"
 )
 
-// executeForEach executes a forEach loop AS A LEAF NODE.
+// executeForEach executes a forEach (or files) loop AS A LEAF NODE.
 // The loop is a single unit in the progress graph, with children executing internally.
 func (i *Itamae) executeForEach(
 	ctx context.Context,
@@ -16,25 +16,58 @@ func (i *Itamae) executeForEach(
 	execCtx *executionContext,
 	result *Result,
 ) error {
-	items, err := i.extractLoopItems(def, execCtx)
+	items, err := i.extractForEachItems(def, execCtx)
 	if err != nil {
 		i.state.setNodeState(def.ID, "error")
 		return err
 	}
 
+	failures, err := newLoopFailures(def, len(items))
+	if err != nil {
+		i.state.setNodeState(def.ID, "error")
+		return newNodeError(def.ID, "loop", "validate", err)
+	}
+
 	i.initializeLoopExecution(def, execCtx)
 
 	start := time.Now()
-	loopResults, err := i.executeForEachIterations(ctx, def, items, execCtx)
+	loopResults, err := i.executeForEachIterations(ctx, def, items, execCtx, failures)
+	if err == nil && isBatchLoop(def) {
+		loopResults, err = flattenBatchResults(def, items, loopResults)
+	}
 	if err != nil {
 		i.state.setNodeState(def.ID, "error")
 		return err
 	}
 
-	i.finalizeLoopExecution(def, loopResults, execCtx, result, time.Since(start))
+	result.LoopFailures = append(result.LoopFailures, failures.list...)
+	i.finalizeLoopExecution(def, failures.output(loopResults), execCtx, result, time.Since(start))
 	return nil
 }
 
+// extractForEachItems returns what a forEach or files loop iterates over:
+// its items (or matching files), or batches of them when batchSize is set.
+func (i *Itamae) extractForEachItems(def *neta.Definition, execCtx *executionContext) ([]interface{}, error) {
+	extract := i.extractLoopItems
+	if def.Parameters["mode"] == "files" {
+		extract = i.extractFileItems
+	}
+
+	items, err := extract(def, execCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize, err := getLoopBatchSize(def)
+	if err != nil {
+		return nil, newNodeError(def.ID, "loop", "validate", err)
+	}
+	if batchSize == 0 {
+		return items, nil
+	}
+	return batchItems(items, batchSize), nil
+}
+
 // initializeLoopExecution sets up loop state and logging.
 func (i *Itamae) initializeLoopExecution(def *neta.Definition, execCtx *executionContext) {
 	i.state.setNodeState(def.ID, "executing")
@@ -49,7 +82,7 @@ func (i *Itamae) initializeLoopExecution(def *neta.Definition, execCtx *executio
 // finalizeLoopExecution completes loop execution and stores results.
 func (i *Itamae) finalizeLoopExecution(
 	def *neta.Definition,
-	loopResults []interface{},
+	loopResults interface{},
 	execCtx *executionContext,
 	result *Result,
 	duration time.Duration,
@@ -60,6 +93,7 @@ func (i *Itamae) finalizeLoopExecution(
 	execCtx.set(def.ID, loopResults)
 	result.NodeOutputs[def.ID] = loopResults
 	result.NodesExecuted++
+	i.checkpointNode(def, loopResults)
 
 	if i.logger != nil {
 		durationStr := formatDuration(duration)
@@ -71,21 +105,22 @@ func (i *Itamae) finalizeLoopExecution(
 	i.notifyProgress(def.ID, "completed")
 }
 
-// checkLoopCancellation checks if context is cancelled.
+// checkLoopCancellation checks if context is cancelled or the run is stopping.
 func (i *Itamae) checkLoopCancellation(ctx context.Context, def *neta.Definition) error {
-	select {
-	case <-ctx.Done():
+	if err := stopErr(ctx); err != nil {
 		i.state.setNodeState(def.ID, "error")
-		return ctx.Err()
-	default:
-		return nil
+		return err
 	}
+	return nil
 }
 
 // reportLoopProgress reports partial progress for current iteration.
 func (i *Itamae) reportLoopProgress(def *neta.Definition, idx, total int) {
 	progress := (idx * 100) / total
 	message := fmt.Sprintf("Iteration %d/%d", idx+1, total)
+	if isBatchLoop(def) {
+		message = fmt.Sprintf("Batch %d/%d", idx+1, total)
+	}
 	i.state.setNodeProgress(def.ID, progress, message)
 
 	if i.logger != nil {
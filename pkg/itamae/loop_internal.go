@@ -13,24 +13,95 @@ func (i *Itamae) executeNodeInternal(
 	def *neta.Definition,
 	execCtx *executionContext,
 ) (interface{}, error) {
+	ctx, release, err := i.acquireResource(ctx, def)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	i.logInternalNodeStart(def, execCtx)
 
+	ctx, span := i.startNodeSpan(ctx, def, execCtx)
+	output, err := i.runNodeInternal(ctx, def, execCtx)
+	i.endSpan(span, err)
+	return output, err
+}
+
+// runNodeInternal loads, executes and caches a loop child.
+func (i *Itamae) runNodeInternal(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+) (interface{}, error) {
+	if def.Type == "bento" {
+		return i.runSubBentoInternal(ctx, def, execCtx)
+	}
+
 	netaImpl, err := i.loadNetaForInternal(def)
 	if err != nil {
 		return nil, err
 	}
 
-	params := i.prepareInternalNodeParams(def, execCtx)
+	params, err := i.prepareInternalNodeParams(def, execCtx)
+	if err != nil {
+		return nil, newNodeError(def.ID, def.Type, "resolve params", err)
+	}
+	key := i.nodeCacheKey(def, params)
+	if output, ok := i.restoreCachedChild(ctx, def, key, execCtx); ok {
+		return output, nil
+	}
 
-	output, duration, err := i.executeInternalNode(ctx, netaImpl, params)
+	var output interface{}
+	var duration time.Duration
+	err = i.executeWithTimeout(ctx, def, func(ctx context.Context) error {
+		output, duration, err = i.executeInternalNode(ctx, def, netaImpl, params, execCtx)
+		if err != nil {
+			return newNodeError(def.ID, def.Type, "execute", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, newNodeError(def.ID, def.Type, "execute", err)
+		return nil, err
 	}
 
+	i.cacheOutput(def, key, output)
 	i.logInternalNodeComplete(def, execCtx, duration)
 	return output, nil
 }
 
+// runSubBentoInternal runs a sub-bento loop child (sub-bentos aren't neta
+// in the pantry). The cache hits of its nodes count for the loop.
+func (i *Itamae) runSubBentoInternal(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+) (interface{}, error) {
+	start := time.Now()
+
+	var output interface{}
+	var childResult *Result
+	err := i.executeWithTimeout(ctx, def, func(ctx context.Context) error {
+		var err error
+		output, err = i.executeWithRetry(ctx, def, execCtx, func() (interface{}, error) {
+			var outputs map[string]interface{}
+			var err error
+			outputs, childResult, err = i.runSubBento(ctx, def, execCtx)
+			return outputs, err
+		})
+		if err != nil {
+			return newNodeError(def.ID, def.Type, "execute", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	countCacheHits(ctx, childResult.NodesCached)
+	i.logInternalNodeComplete(def, execCtx, time.Since(start))
+	return output, nil
+}
+
 // logInternalNodeStart logs execution start for internal node.
 func (i *Itamae) logInternalNodeStart(def *neta.Definition, execCtx *executionContext) {
 	if i.logger != nil {
@@ -52,35 +123,31 @@ func (i *Itamae) loadNetaForInternal(def *neta.Definition) (neta.Executable, err
 func (i *Itamae) prepareInternalNodeParams(
 	def *neta.Definition,
 	execCtx *executionContext,
-) map[string]interface{} {
-	params := make(map[string]interface{})
-	for k, v := range def.Parameters {
-		params[k] = execCtx.resolveValue(v)
+) (map[string]interface{}, error) {
+	params, err := execCtx.resolveParams(def.Parameters)
+	if err != nil {
+		return nil, err
 	}
 	params["_context"] = execCtx.toMap()
-	params["_onOutput"] = func(line string) {
-		if i.logger != nil {
-			// Stream output with breadcrumb context
-			breadcrumb := execCtx.getBreadcrumb()
-			if breadcrumb != "" {
-				formattedLine := formatStreamingOutput(breadcrumb, line)
-				i.logger.Info(formattedLine)
-			} else {
-				i.logger.Info(line)
-			}
-		}
+	params["_onOutput"] = i.streamOutput(def, execCtx)
+	if execCtx.inputs != nil {
+		params["_inputs"] = execCtx.inputs
 	}
-	return params
+	return params, nil
 }
 
-// executeInternalNode executes neta and tracks duration.
+// executeInternalNode executes neta (with its retry policy) and tracks duration.
 func (i *Itamae) executeInternalNode(
 	ctx context.Context,
+	def *neta.Definition,
 	netaImpl neta.Executable,
 	params map[string]interface{},
+	execCtx *executionContext,
 ) (interface{}, time.Duration, error) {
 	start := time.Now()
-	output, err := netaImpl.Execute(ctx, params)
+	output, err := i.executeWithRetry(ctx, def, execCtx, func() (interface{}, error) {
+		return netaImpl.Execute(ctx, params)
+	})
 	return output, time.Since(start), err
 }
 
@@ -10,21 +10,23 @@ import (
 
 // executeForEachIterations executes all loop iterations and returns results.
 // Supports concurrent execution via maxConcurrency parameter.
+// Failed iterations the loop tolerates are recorded in failures.
 func (i *Itamae) executeForEachIterations(
 	ctx context.Context,
 	def *neta.Definition,
 	items []interface{},
 	execCtx *executionContext,
+	failures *loopFailures,
 ) ([]interface{}, error) {
 	// Check for maxConcurrency parameter
 	maxConcurrency := i.getLoopConcurrency(def)
 
 	if maxConcurrency > 1 {
-		return i.executeForEachConcurrent(ctx, def, items, execCtx, maxConcurrency)
+		return i.executeForEachConcurrent(ctx, def, items, execCtx, maxConcurrency, failures)
 	}
 
 	// Sequential execution (original behavior)
-	return i.executeForEachSequential(ctx, def, items, execCtx)
+	return i.executeForEachSequential(ctx, def, items, execCtx, failures)
 }
 
 // getLoopConcurrency extracts maxConcurrency parameter from loop definition.
@@ -46,10 +48,10 @@ func (i *Itamae) executeForEachSequential(
 	def *neta.Definition,
 	items []interface{},
 	execCtx *executionContext,
+	failures *loopFailures,
 ) ([]interface{}, error) {
 	loopResults := make([]interface{}, 0, len(items))
 	totalItems := len(items)
-	continueOnError := i.getLoopContinueOnError(def)
 
 	for idx, item := range items {
 		if err := i.checkLoopCancellation(ctx, def); err != nil {
@@ -61,13 +63,16 @@ func (i *Itamae) executeForEachSequential(
 		iterResult, err := i.executeLoopIteration(ctx, def, item, idx, totalItems, execCtx)
 		if err != nil {
 			i.logLoopIterationError(def, idx, err)
-			if continueOnError {
-				// Skip this iteration but continue with others
-				loopResults = append(loopResults, nil)
-				continue
+			if !failures.tolerant || stopErr(ctx) != nil {
+				// Fail fast
+				return nil, fmt.Errorf("iteration %d failed: %w", idx, err)
 			}
-			// Fail fast
-			return nil, fmt.Errorf("iteration %d failed: %w", idx, err)
+			if err := failures.record(def, idx, item, err); err != nil {
+				return nil, err
+			}
+			// Skip this iteration but continue with others
+			loopResults = append(loopResults, nil)
+			continue
 		}
 
 		loopResults = append(loopResults, iterResult)
@@ -77,16 +82,18 @@ func (i *Itamae) executeForEachSequential(
 }
 
 // executeForEachConcurrent executes iterations concurrently with worker pool.
+// The first error, or a failure beyond the loop's limits, cancels the
+// iterations in flight and those not started yet.
 func (i *Itamae) executeForEachConcurrent(
 	ctx context.Context,
 	def *neta.Definition,
 	items []interface{},
 	execCtx *executionContext,
 	maxConcurrency int,
+	failures *loopFailures,
 ) ([]interface{}, error) {
 	totalItems := len(items)
 	results := make([]interface{}, totalItems)
-	continueOnError := i.getLoopContinueOnError(def)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -96,10 +103,21 @@ func (i *Itamae) executeForEachConcurrent(
 	// Create semaphore for concurrency control
 	sem := make(chan struct{}, maxConcurrency)
 
+	iterCtx, cancel := withCancelScope(ctx)
+	defer cancel()
+
+	// fail records the error that stops the loop (mu held)
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
 	for idx, item := range items {
 		// Check cancellation before starting new iteration
-		if err := ctx.Err(); err != nil {
-			return nil, err
+		if stopErr(iterCtx) != nil {
+			break
 		}
 
 		wg.Add(1)
@@ -109,25 +127,29 @@ func (i *Itamae) executeForEachConcurrent(
 			// Acquire semaphore
 			sem <- struct{}{}
 			defer func() { <-sem }()
+			if stopErr(iterCtx) != nil {
+				return
+			}
 
 			// Execute iteration
-			iterResult, err := i.executeLoopIteration(ctx, def, itm, index, totalItems, execCtx)
+			iterResult, err := i.executeLoopIteration(iterCtx, def, itm, index, totalItems, execCtx)
 
 			// Update results and progress
 			mu.Lock()
 			defer mu.Unlock()
 
 			if err != nil {
-				if continueOnError {
+				if failures.tolerant && stopErr(iterCtx) == nil {
 					// Log error but continue with other iterations
 					i.logLoopIterationError(def, index, err)
 					results[index] = nil // Mark as skipped
-				} else {
-					// Fail fast - capture first error
-					if firstErr == nil {
-						firstErr = fmt.Errorf("iteration %d failed: %w", index, err)
-						i.logLoopIterationError(def, index, err)
+					if err := failures.record(def, index, itm, err); err != nil {
+						fail(err)
 					}
+				} else if firstErr == nil {
+					// Fail fast - capture first error
+					i.logLoopIterationError(def, index, err)
+					fail(fmt.Errorf("iteration %d failed: %w", index, err))
 				}
 			} else {
 				results[index] = iterResult
@@ -144,20 +166,13 @@ func (i *Itamae) executeForEachConcurrent(
 	if firstErr != nil {
 		return nil, firstErr
 	}
+	if err := stopErr(ctx); err != nil {
+		return nil, err
+	}
 
 	return results, nil
 }
 
-// getLoopContinueOnError checks if loop should continue on iteration errors.
-func (i *Itamae) getLoopContinueOnError(def *neta.Definition) bool {
-	if coe, ok := def.Parameters["continueOnError"]; ok {
-		if coeBool, ok := coe.(bool); ok {
-			return coeBool
-		}
-	}
-	return false
-}
-
 // executeLoopIteration executes one iteration (INTERNAL - not tracked in graph).
 func (i *Itamae) executeLoopIteration(
 	ctx context.Context,
@@ -168,16 +183,36 @@ func (i *Itamae) executeLoopIteration(
 	execCtx *executionContext,
 ) (map[string]interface{}, error) {
 	iterCtx := execCtx.withNode(def.Name)
-	iterCtx.set("item", item)
-	iterCtx.set("index", idx)
+	setIterationVars(def, iterCtx, item, idx, total)
 
-	return i.executeIterationChildren(ctx, def, idx, total, iterCtx)
+	return i.executeIterationChildren(ctx, def, item, idx, total, iterCtx)
 }
 
 // executeIterationChildren executes all child nodes for one iteration.
+// A checkpointed iteration is restored instead if it ran the same item.
 func (i *Itamae) executeIterationChildren(
 	ctx context.Context,
 	def *neta.Definition,
+	item interface{},
+	idx int,
+	total int,
+	iterCtx *executionContext,
+) (map[string]interface{}, error) {
+	if restored, ok := i.restoredIteration(def, idx, item); ok {
+		return restored, nil
+	}
+
+	ctx, span := i.startIterationSpan(ctx, def, idx, total)
+	iterResult, err := i.runIterationChildren(ctx, def, item, idx, total, iterCtx)
+	i.endSpan(span, err)
+	return iterResult, err
+}
+
+// runIterationChildren runs the child nodes of one iteration in order.
+func (i *Itamae) runIterationChildren(
+	ctx context.Context,
+	def *neta.Definition,
+	item interface{},
 	idx int,
 	total int,
 	iterCtx *executionContext,
@@ -186,6 +221,13 @@ func (i *Itamae) executeIterationChildren(
 
 	for j := range def.Nodes {
 		childDef := &def.Nodes[j]
+		skip, err := i.skipLoopChildWhen(childDef, iterCtx)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			continue
+		}
 
 		// Notify messenger which child is currently executing
 		if i.messenger != nil {
@@ -201,5 +243,6 @@ func (i *Itamae) executeIterationChildren(
 		iterCtx.set(childDef.ID, output)
 	}
 
+	i.checkpointIteration(def, idx, item, iterResult)
 	return iterResult, nil
 }
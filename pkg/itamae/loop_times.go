@@ -74,7 +74,7 @@ func (i *Itamae) executeTimesIteration(
 	iterCtx.set("iteration", iteration)
 	iterCtx.set("index", iteration) // Alias for consistency with forEach
 
-	return i.executeIterationChildren(ctx, def, iteration, total, iterCtx)
+	return i.executeIterationChildren(ctx, def, iteration, iteration, total, iterCtx)
 }
 
 // extractTimesCount extracts and validates count parameter for times loop.
@@ -0,0 +1,216 @@
+package itamae
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// defaultWhileMaxIterations is the safety limit for while loops without maxIterations.
+// Matches the limit used by the standalone loop neta.
+const defaultWhileMaxIterations = 1000
+
+// whileOptions are the parameters of a while loop.
+type whileOptions struct {
+	condition     string
+	maxIterations int
+	interval      time.Duration // Wait between passes (0 = none), e.g. to poll a folder
+}
+
+// executeWhile executes a while loop AS A LEAF NODE.
+// The condition is evaluated before each pass; the loop fails if it is still
+// true after maxIterations passes.
+func (i *Itamae) executeWhile(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+) error {
+	opts, err := i.extractWhileParams(def)
+	if err != nil {
+		i.state.setNodeState(def.ID, "error")
+		return err
+	}
+
+	i.initializeLoopExecution(def, execCtx)
+
+	start := time.Now()
+	loopResults, err := i.executeWhileIterations(ctx, def, opts, execCtx)
+	if err != nil {
+		i.state.setNodeState(def.ID, "error")
+		return err
+	}
+
+	i.finalizeLoopExecution(def, loopResults, execCtx, result, time.Since(start))
+	return nil
+}
+
+// executeWhileIterations runs iterations until the condition is false,
+// waiting for the interval between passes.
+func (i *Itamae) executeWhileIterations(
+	ctx context.Context,
+	def *neta.Definition,
+	opts whileOptions,
+	execCtx *executionContext,
+) ([]interface{}, error) {
+	loopResults := make([]interface{}, 0)
+	var previous map[string]interface{}
+
+	for iteration := 0; ; iteration++ {
+		if iteration > 0 {
+			waitWhileInterval(ctx, opts.interval)
+		}
+		if err := i.checkLoopCancellation(ctx, def); err != nil {
+			return nil, err
+		}
+
+		shouldContinue, err := i.evaluateWhileCondition(def, opts.condition, iteration, previous, execCtx)
+		if err != nil {
+			return nil, err
+		}
+		if !shouldContinue {
+			return loopResults, nil
+		}
+
+		if iteration >= opts.maxIterations {
+			return nil, newNodeError(def.ID, "loop", "execute",
+				fmt.Errorf("condition still true after maxIterations (%d)", opts.maxIterations))
+		}
+
+		i.reportLoopProgress(def, iteration, opts.maxIterations)
+
+		iterResult, err := i.executeWhileIteration(ctx, def, iteration, opts.maxIterations, previous, execCtx)
+		if err != nil {
+			i.logLoopIterationError(def, iteration, err)
+			return nil, fmt.Errorf("iteration %d failed: %w", iteration, err)
+		}
+
+		loopResults = append(loopResults, iterResult)
+		previous = iterResult
+	}
+}
+
+// executeWhileIteration executes one iteration (INTERNAL - not tracked in graph).
+func (i *Itamae) executeWhileIteration(
+	ctx context.Context,
+	def *neta.Definition,
+	iteration int,
+	maxIterations int,
+	previous map[string]interface{},
+	execCtx *executionContext,
+) (map[string]interface{}, error) {
+	iterCtx := execCtx.withNode(def.Name)
+	for k, v := range whileIterationData(iteration, previous) {
+		iterCtx.set(k, v)
+	}
+
+	return i.executeIterationChildren(ctx, def, previous, iteration, maxIterations, iterCtx)
+}
+
+// evaluateWhileCondition evaluates the loop condition against the iteration context.
+func (i *Itamae) evaluateWhileCondition(
+	def *neta.Definition,
+	condition string,
+	iteration int,
+	previous map[string]interface{},
+	execCtx *executionContext,
+) (bool, error) {
+	env := execCtx.toMap()
+	for k, v := range whileIterationData(iteration, previous) {
+		env[k] = v
+	}
+
+	program, err := expr.Compile(condition, expr.Env(env), expr.AllowUndefinedVariables())
+	if err != nil {
+		return false, newNodeError(def.ID, "loop", "evaluate condition",
+			fmt.Errorf("failed to compile condition: %w", err))
+	}
+
+	output, err := expr.Run(program, env)
+	if err != nil {
+		return false, newNodeError(def.ID, "loop", "evaluate condition",
+			fmt.Errorf("failed to evaluate condition: %w", err))
+	}
+
+	shouldContinue, ok := output.(bool)
+	if !ok {
+		return false, newNodeError(def.ID, "loop", "evaluate condition",
+			fmt.Errorf("condition must evaluate to boolean, got %T", output))
+	}
+	return shouldContinue, nil
+}
+
+// whileIterationData returns the iteration counter and previous outputs.
+// Previous child outputs are available both as "previous" and by child node ID
+// (undefined before the first pass, so conditions can guard with "iteration == 0").
+func whileIterationData(iteration int, previous map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(previous)+3)
+	for childID, output := range previous {
+		data[childID] = output
+	}
+	data["iteration"] = iteration
+	data["index"] = iteration // Alias for consistency with forEach
+	data["previous"] = previous
+	return data
+}
+
+// waitWhileInterval waits between two passes of a while loop. It returns
+// early once the context is done or the run is stopping, which the loop's
+// cancellation check then reports.
+func waitWhileInterval(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-runContext(ctx).Done():
+	case <-timer.C:
+	}
+}
+
+// extractWhileParams extracts and validates condition, maxIterations and
+// interval for while loop.
+func (i *Itamae) extractWhileParams(def *neta.Definition) (whileOptions, error) {
+	condition, ok := def.Parameters["condition"].(string)
+	if !ok || condition == "" {
+		return whileOptions{}, newNodeError(def.ID, "loop", "validate",
+			fmt.Errorf("'condition' must be a non-empty string"))
+	}
+
+	maxIterations := defaultWhileMaxIterations
+	if raw, ok := def.Parameters["maxIterations"]; ok {
+		switch v := raw.(type) {
+		case float64:
+			maxIterations = int(v)
+		case int:
+			maxIterations = v
+		default:
+			return whileOptions{}, newNodeError(def.ID, "loop", "validate",
+				fmt.Errorf("'maxIterations' must be a number"))
+		}
+	}
+	if maxIterations < 1 {
+		return whileOptions{}, newNodeError(def.ID, "loop", "validate",
+			fmt.Errorf("'maxIterations' must be at least 1, got %d", maxIterations))
+	}
+
+	var interval time.Duration
+	if raw, ok := def.Parameters["interval"]; ok {
+		s, _ := raw.(string)
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return whileOptions{}, newNodeError(def.ID, "loop", "validate",
+				fmt.Errorf("'interval' must be a positive duration (e.g. \"30s\"), got %v", raw))
+		}
+		interval = d
+	}
+
+	return whileOptions{condition: condition, maxIterations: maxIterations, interval: interval}, nil
+}
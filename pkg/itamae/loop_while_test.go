@@ -0,0 +1,160 @@
+package itamae_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/neta/library/editfields"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// whileBento builds a bento with a single while loop around an edit-fields child.
+func whileBento(params map[string]interface{}) *neta.Definition {
+	return &neta.Definition{
+		ID:   "while-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{
+				ID:         "poll",
+				Type:       "loop",
+				Name:       "Poll",
+				Parameters: params,
+				Nodes: []neta.Definition{
+					{
+						ID:   "step",
+						Type: "edit-fields",
+						Parameters: map[string]interface{}{
+							"values": map[string]interface{}{"count": "{{.iteration}}"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newWhileChef creates an itamae with edit-fields registered.
+func newWhileChef() *itamae.Itamae {
+	p := pantry.New()
+	p.RegisterFactory("edit-fields", func() neta.Executable {
+		return editfields.New()
+	})
+	return itamae.New(p, nil)
+}
+
+// TestItamae_WhileLoop tests that the condition is evaluated before each pass.
+func TestItamae_WhileLoop(t *testing.T) {
+	bento := whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "iteration < 3",
+	})
+
+	result, err := newWhileChef().Serve(context.Background(), bento)
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	loopOutput, ok := result.NodeOutputs["poll"].([]interface{})
+	if !ok {
+		t.Fatalf("Loop output is not []interface{}")
+	}
+	if len(loopOutput) != 3 {
+		t.Errorf("Loop output length = %d, want 3", len(loopOutput))
+	}
+}
+
+// TestItamae_WhileLoopPreviousOutputs tests that the previous iteration's outputs are exposed.
+func TestItamae_WhileLoopPreviousOutputs(t *testing.T) {
+	bento := whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "iteration == 0 || step.count < 4",
+	})
+
+	result, err := newWhileChef().Serve(context.Background(), bento)
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	loopOutput := result.NodeOutputs["poll"].([]interface{})
+	if len(loopOutput) != 5 {
+		t.Errorf("Loop output length = %d, want 5 (stop once step.count reaches 4)", len(loopOutput))
+	}
+}
+
+// TestItamae_WhileLoopMaxIterations tests the infinite loop guard.
+func TestItamae_WhileLoopMaxIterations(t *testing.T) {
+	bento := whileBento(map[string]interface{}{
+		"mode":          "while",
+		"condition":     "true",
+		"maxIterations": float64(5),
+	})
+
+	_, err := newWhileChef().Serve(context.Background(), bento)
+	if err == nil {
+		t.Fatal("Expected error when maxIterations is exceeded")
+	}
+	if !strings.Contains(err.Error(), "maxIterations (5)") {
+		t.Errorf("Error should mention maxIterations: %v", err)
+	}
+}
+
+// TestItamae_WhileLoopNonBooleanCondition tests that conditions must be boolean.
+func TestItamae_WhileLoopNonBooleanCondition(t *testing.T) {
+	bento := whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "iteration + 1",
+	})
+
+	_, err := newWhileChef().Serve(context.Background(), bento)
+	if err == nil || !strings.Contains(err.Error(), "boolean") {
+		t.Errorf("Expected boolean condition error, got %v", err)
+	}
+}
+
+// TestItamae_WhileLoopInterval tests the wait between passes, which ends
+// early when the run is cancelled.
+func TestItamae_WhileLoopInterval(t *testing.T) {
+	start := time.Now()
+	_, err := newWhileChef().Serve(context.Background(), whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "iteration < 3",
+		"interval":  "30ms",
+	}))
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Serve took %v, want at least 3 intervals", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = newWhileChef().Serve(ctx, whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "true",
+		"interval":  "5s",
+	}))
+	if err == nil {
+		t.Fatal("Expected error from the cancelled run")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Serve took %v, the interval ignored cancellation", elapsed)
+	}
+}
+
+// TestItamae_WhileLoopInvalidInterval tests that intervals must be positive durations.
+func TestItamae_WhileLoopInvalidInterval(t *testing.T) {
+	_, err := newWhileChef().Serve(context.Background(), whileBento(map[string]interface{}{
+		"mode":      "while",
+		"condition": "iteration < 3",
+		"interval":  "soon",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "'interval' must be a positive duration") {
+		t.Errorf("Expected interval error, got %v", err)
+	}
+}
@@ -107,6 +107,29 @@ func msgBentoFailed(duration string) logMessage {
 	}
 }
 
+// msgBentoCancelled creates a message for a cancelled bento execution.
+// Format: "🛑 Cancelled! Bento execution stopped after [duration]"
+func msgBentoCancelled(duration string) logMessage {
+	return logMessage{
+		emoji:    "🛑",
+		text:     "Cancelled! Bento execution stopped after " + duration,
+		isFailed: true,
+	}
+}
+
+// msgGroupCancelled creates a message for running a group's onCancel nodes.
+func msgGroupCancelled(breadcrumb, name string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji:     "",
+		text:      prefix + " Cancelled NETA:group " + name + " (running onCancel)",
+		isRunning: true,
+	}
+}
+
 // msgNetaStarted creates a message for neta execution start.
 func msgNetaStarted() logMessage {
 	return logMessage{
@@ -204,3 +227,85 @@ func msgChildNodeCompleted(breadcrumb, nodeType, name, duration string, progress
 		isSuccess: true,
 	}
 }
+
+// msgNodeRestored creates a message for a node restored from a checkpoint.
+// Format: "[Parent:Child] Restored NETA:type name (checkpoint)"
+func msgNodeRestored(breadcrumb, nodeType, name string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji:     "",
+		text:      prefix + " Restored NETA:" + nodeType + " " + name + " (checkpoint)",
+		isSuccess: true,
+	}
+}
+
+// msgNodeCached creates a message for a node served from the output cache.
+// Format: "[Parent:Child] Cached NETA:type name (cache hit)"
+func msgNodeCached(breadcrumb, nodeType, name string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji:     "",
+		text:      prefix + " Cached NETA:" + nodeType + " " + name + " (cache hit)",
+		isSuccess: true,
+	}
+}
+
+// msgNodeSkipped creates a message for a node on an inactive branch.
+// Format: "[Parent:Child] Skipped NETA:type name (inactive branch)"
+func msgNodeSkipped(breadcrumb, nodeType, name string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji: "",
+		text:  prefix + " Skipped NETA:" + nodeType + " " + name + " (inactive branch)",
+	}
+}
+
+// msgNodeSkippedWhen creates a message for a node whose when condition is false.
+// Format: "[Parent:Child] Skipped NETA:type name (when: condition)"
+func msgNodeSkippedWhen(breadcrumb, nodeType, name, condition string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji: "",
+		text:  prefix + " Skipped NETA:" + nodeType + " " + name + " (when: " + condition + ")",
+	}
+}
+
+// msgNodeRetrying creates a message for a node about to be retried.
+// Format: "[Parent:Child] Retrying NETA:type name (attempt 2/3 in 1s)"
+func msgNodeRetrying(breadcrumb, nodeType, name string, attempt, maxAttempts int, delay string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji:     "",
+		text:      fmt.Sprintf("%s Retrying NETA:%s %s (attempt %d/%d in %s)", prefix, nodeType, name, attempt, maxAttempts, delay),
+		isRunning: true,
+	}
+}
+
+// msgGroupCaught creates a message for a group running its catch nodes.
+// Format: "[Parent:Child] Caught NETA:group name (failed at node-id)"
+func msgGroupCaught(breadcrumb, name, failedNodeID string) logMessage {
+	prefix := ""
+	if breadcrumb != "" {
+		prefix = "[" + breadcrumb + "]"
+	}
+	return logMessage{
+		emoji:     "",
+		text:      prefix + " Caught NETA:group " + name + " (failed at " + failedNodeID + ")",
+		isRunning: true,
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 // executeParallel executes a parallel neta.
-// Runs all child nodes concurrently using goroutines.
+// Runs all child nodes concurrently using goroutines; its errorStrategy
+// parameter decides what a failed child means for the others and the parallel.
 func (i *Itamae) executeParallel(
 	ctx context.Context,
 	def *neta.Definition,
@@ -45,6 +46,15 @@ func (i *Itamae) executeParallel(
 	// Track execution time for messenger
 	start := time.Now()
 
+	strategy, err := getErrorStrategy(def)
+	if err != nil {
+		err = newNodeError(def.ID, "parallel", "validate", err)
+		if i.messenger != nil {
+			i.messenger.SendNodeCompleted(def.ID, time.Since(start), err)
+		}
+		return err
+	}
+
 	// Get max concurrency (default: no limit)
 	maxConcurrency := 0
 	if mc, ok := def.Parameters["maxConcurrency"]; ok {
@@ -53,10 +63,61 @@ func (i *Itamae) executeParallel(
 		}
 	}
 
-	// Execute children in parallel
+	children := i.runParallelChildren(ctx, def, execCtx, result, maxConcurrency, strategy)
+
+	duration := time.Since(start)
+
+	if err := children.err(def, strategy); err != nil {
+		// Send messenger event: node completed with error
+		if i.messenger != nil {
+			i.messenger.SendNodeCompleted(def.ID, duration, err)
+		}
+		return err
+	}
+
+	if strategy == errorStrategyBestEffort {
+		output := children.output(def)
+		execCtx.set(def.ID, output)
+		result.NodeOutputs[def.ID] = output
+	}
+
+	i.notifyProgress(def.ID, "completed")
+	result.NodesExecuted++
+
+	// Send messenger event: node completed
+	if i.messenger != nil {
+		i.messenger.SendNodeCompleted(def.ID, duration, nil)
+	}
+
+	if i.logger != nil {
+		i.logger.Info("✓ Parallel execution completed",
+			"parallel_id", def.ID,
+			"child_count", childCount)
+	}
+
+	return nil
+}
+
+// runParallelChildren executes the children concurrently and merges their
+// results, including outputs of failed children's completed nodes.
+// With failFast the first failure cancels the children still running.
+func (i *Itamae) runParallelChildren(
+	ctx context.Context,
+	def *neta.Definition,
+	execCtx *executionContext,
+	result *Result,
+	maxConcurrency int,
+	strategy string,
+) *childErrors {
+	cancel := func() {}
+	if strategy == errorStrategyFailFast {
+		ctx, cancel = withCancelScope(ctx)
+		defer cancel()
+	}
+
+	children := newChildErrors(len(def.Nodes))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
-	var firstErr error
 
 	// Create semaphore for concurrency control
 	var sem chan struct{}
@@ -66,9 +127,10 @@ func (i *Itamae) executeParallel(
 
 	for idx := range def.Nodes {
 		child := &def.Nodes[idx]
+		childCtx := execCtx.copy()
 
 		wg.Add(1)
-		go func(node *neta.Definition) {
+		go func(idx int, node *neta.Definition) {
 			defer wg.Done()
 
 			// Acquire semaphore if concurrency limited
@@ -77,60 +139,24 @@ func (i *Itamae) executeParallel(
 				defer func() { <-sem }()
 			}
 
-			// Create child context
-			childCtx := execCtx.copy()
-
-			// Execute child
 			childResult := &Result{
 				NodeOutputs: make(map[string]interface{}),
 			}
-
 			err := i.executeNode(ctx, node, childCtx, childResult)
 
-			// Handle error (capture first error only)
 			mu.Lock()
-			if err != nil && firstErr == nil {
-				firstErr = err
+			defer mu.Unlock()
+			mergeNodeOutcome(nodeOutcome{node: node, result: childResult, err: err}, execCtx, result)
+			if children.record(idx, err) && strategy == errorStrategyFailFast {
+				cancel()
 			}
-
-			// Merge child outputs
-			if err == nil {
-				for k, v := range childResult.NodeOutputs {
-					result.NodeOutputs[k] = v
-				}
-				result.NodesExecuted += childResult.NodesExecuted
+			if err != nil && strategy == errorStrategyBestEffort && i.logger != nil {
+				i.logger.Warn("Parallel child failed", "parallel_id", def.ID, "child_id", node.ID, "error", err)
 			}
-			mu.Unlock()
-		}(child)
+		}(idx, child)
 	}
 
 	// Wait for all goroutines to complete
 	wg.Wait()
-
-	duration := time.Since(start)
-
-	if firstErr != nil {
-		err := newNodeError(def.ID, "parallel", "execute", firstErr)
-		// Send messenger event: node completed with error
-		if i.messenger != nil {
-			i.messenger.SendNodeCompleted(def.ID, duration, err)
-		}
-		return err
-	}
-
-	i.notifyProgress(def.ID, "completed")
-	result.NodesExecuted++
-
-	// Send messenger event: node completed
-	if i.messenger != nil {
-		i.messenger.SendNodeCompleted(def.ID, duration, nil)
-	}
-
-	if i.logger != nil {
-		i.logger.Info("✓ Parallel execution completed",
-			"parallel_id", def.ID,
-			"child_count", childCount)
-	}
-
-	return nil
+	return children
 }
@@ -0,0 +1,123 @@
+package itamae
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Error strategies of parallel nodes (errorStrategy parameter).
+const (
+	errorStrategyFailFast   = "failFast"   // First failure cancels the other children
+	errorStrategyCollectAll = "collectAll" // All children run, every failure is reported
+	errorStrategyBestEffort = "bestEffort" // All children run, failures don't fail the parallel
+)
+
+// getErrorStrategy extracts the errorStrategy parameter (default: failFast).
+func getErrorStrategy(def *neta.Definition) (string, error) {
+	strategy, ok := def.Parameters["errorStrategy"]
+	if !ok {
+		return errorStrategyFailFast, nil
+	}
+
+	switch strategy {
+	case errorStrategyFailFast, errorStrategyCollectAll, errorStrategyBestEffort:
+		return strategy.(string), nil
+	default:
+		return "", fmt.Errorf("'errorStrategy' must be failFast, collectAll or bestEffort, got %v", strategy)
+	}
+}
+
+// childErrors holds the errors of a parallel node's children by position,
+// and which child failed first.
+type childErrors struct {
+	errs  []error
+	first int // -1 while no child failed
+}
+
+// newChildErrors creates an empty record for count children.
+func newChildErrors(count int) *childErrors {
+	return &childErrors{errs: make([]error, count), first: -1}
+}
+
+// record stores a child's error. Reports whether it is the first failure.
+func (c *childErrors) record(idx int, err error) bool {
+	if err == nil {
+		return false
+	}
+	c.errs[idx] = err
+	if c.first >= 0 {
+		return false
+	}
+	c.first = idx
+	return true
+}
+
+// failed returns the IDs and errors of the failed children in declaration order.
+func (c *childErrors) failed(def *neta.Definition) ([]string, []error) {
+	var ids []string
+	var errs []error
+	for idx, err := range c.errs {
+		if err != nil {
+			ids = append(ids, def.Nodes[idx].ID)
+			errs = append(errs, err)
+		}
+	}
+	return ids, errs
+}
+
+// err returns the parallel node's error under strategy, nil if it succeeds.
+func (c *childErrors) err(def *neta.Definition, strategy string) error {
+	if c.first < 0 || strategy == errorStrategyBestEffort {
+		return nil
+	}
+	if strategy == errorStrategyFailFast {
+		// Later failures are the siblings it cancelled
+		return newNodeError(def.ID, "parallel", "execute", c.errs[c.first])
+	}
+	ids, errs := c.failed(def)
+	return newNodeError(def.ID, "parallel", "execute", &parallelError{
+		childIDs: ids,
+		errs:     errs,
+		total:    len(c.errs),
+	})
+}
+
+// output returns a bestEffort parallel's own output: the failed children's
+// errors by child ID and how many children succeeded and failed.
+func (c *childErrors) output(def *neta.Definition) map[string]interface{} {
+	errs := make(map[string]interface{})
+	for idx, err := range c.errs {
+		if err != nil {
+			errs[def.Nodes[idx].ID] = err.Error()
+		}
+	}
+	return map[string]interface{}{
+		"errors":    errs,
+		"succeeded": len(c.errs) - len(errs),
+		"failed":    len(errs),
+	}
+}
+
+// parallelError reports every failed child of a collectAll parallel.
+type parallelError struct {
+	childIDs []string // IDs of the failed children, in declaration order
+	errs     []error  // Their errors
+	total    int      // Number of children
+}
+
+// Error names the failed children, followed by each of their errors.
+func (e *parallelError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for idx, err := range e.errs {
+		msgs[idx] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d children failed (%s): %s",
+		len(e.errs), e.total, strings.Join(e.childIDs, ", "), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the children's errors.
+func (e *parallelError) Unwrap() []error {
+	return e.errs
+}
@@ -0,0 +1,152 @@
+package itamae_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// parallelBento builds a parallel node over the given children, followed by
+// a report node reading the outputs of the parallel and of its child "ok".
+func parallelBento(strategy string, children ...neta.Definition) *neta.Definition {
+	params := map[string]interface{}{}
+	if strategy != "" {
+		params["errorStrategy"] = strategy
+	}
+
+	return &neta.Definition{
+		ID:   "downloads-bento",
+		Type: "group",
+		Nodes: []neta.Definition{
+			{ID: "downloads", Type: "parallel", Parameters: params, Nodes: children},
+			{ID: "report", Type: "sleepy", Parameters: map[string]interface{}{
+				"delay": "0s",
+				"value": "{{.ok.value}} {{.downloads.failed}}",
+			}},
+		},
+		Edges: []neta.Edge{{ID: "e1", Source: "downloads", Target: "report"}},
+	}
+}
+
+// sleepy returns a sleepy child node.
+func sleepy(id, delay string, fail bool) neta.Definition {
+	return neta.Definition{ID: id, Type: "sleepy", Parameters: map[string]interface{}{
+		"delay": delay,
+		"value": id,
+		"fail":  fail,
+	}}
+}
+
+// TestItamae_ParallelFailFast tests that the first failure cancels the siblings.
+func TestItamae_ParallelFailFast(t *testing.T) {
+	for _, strategy := range []string{"", "failFast"} {
+		cancelled := make(chan struct{}, 2)
+		chef := itamae.New(newSleepyPantry(cancelled), nil)
+
+		start := time.Now()
+		_, err := chef.Serve(context.Background(), parallelBento(strategy,
+			sleepy("textures", "20ms", true),
+			sleepy("models", "5s", false),
+			sleepy("csv", "5s", false),
+		))
+		if err == nil || !strings.Contains(err.Error(), "texture download failed") {
+			t.Fatalf("strategy %q: error = %v, want the texture failure", strategy, err)
+		}
+		if strings.Contains(err.Error(), "children failed") {
+			t.Errorf("strategy %q: cancelled siblings should not be reported: %v", strategy, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("strategy %q: took %v, siblings were not cancelled", strategy, elapsed)
+		}
+		for range 2 {
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Errorf("strategy %q: in-flight sibling did not observe cancellation", strategy)
+			}
+		}
+	}
+}
+
+// TestItamae_ParallelFailFastOnCancel tests that cancelled sibling groups
+// run their onCancel nodes.
+func TestItamae_ParallelFailFastOnCancel(t *testing.T) {
+	cancelled := make(chan struct{}, 1)
+	chef := itamae.New(newSleepyPantry(cancelled), nil)
+
+	result, _ := chef.Serve(context.Background(), parallelBento("failFast",
+		sleepy("textures", "20ms", true),
+		neta.Definition{
+			ID:       "upload",
+			Type:     "group",
+			Nodes:    []neta.Definition{sleepy("models", "5s", false)},
+			OnCancel: []neta.Definition{sleepy("rollback", "0s", false)},
+		},
+	))
+	if _, ok := result.NodeOutputs["rollback"]; !ok {
+		t.Errorf("onCancel node of the cancelled sibling did not run, outputs: %v", result.NodeOutputs)
+	}
+}
+
+// TestItamae_ParallelCollectAll tests that every child runs and every failure is reported.
+func TestItamae_ParallelCollectAll(t *testing.T) {
+	cancelled := make(chan struct{}, 3)
+	chef := itamae.New(newSleepyPantry(cancelled), nil)
+
+	result, err := chef.Serve(context.Background(), parallelBento("collectAll",
+		sleepy("textures", "10ms", true),
+		sleepy("models", "20ms", true),
+		sleepy("ok", "50ms", false),
+	))
+	if err == nil || !strings.Contains(err.Error(), "2 of 3 children failed (textures, models)") {
+		t.Fatalf("error = %v, want both failed children named", err)
+	}
+	if _, ok := result.NodeOutputs["ok"]; !ok {
+		t.Error("output of the successful child was discarded")
+	}
+	if len(cancelled) != 0 {
+		t.Errorf("%d children cancelled, want none", len(cancelled))
+	}
+}
+
+// TestItamae_ParallelBestEffort tests that failures leave partial outputs and an errors map.
+func TestItamae_ParallelBestEffort(t *testing.T) {
+	cancelled := make(chan struct{}, 2)
+	chef := itamae.New(newSleepyPantry(cancelled), nil)
+
+	result, err := chef.Serve(context.Background(), parallelBento("bestEffort",
+		sleepy("textures", "10ms", true),
+		sleepy("ok", "20ms", false),
+	))
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	output := result.NodeOutputs["downloads"].(map[string]interface{})
+	if output["succeeded"] != 1 || output["failed"] != 1 {
+		t.Errorf("succeeded/failed = %v/%v, want 1/1", output["succeeded"], output["failed"])
+	}
+	errs := output["errors"].(map[string]interface{})
+	if msg, _ := errs["textures"].(string); !strings.Contains(msg, "texture download failed") {
+		t.Errorf("errors = %v, want the textures failure", errs)
+	}
+
+	report := result.NodeOutputs["report"].(map[string]interface{})
+	if report["value"] != "ok 1" {
+		t.Errorf("report value = %v, want %q", report["value"], "ok 1")
+	}
+}
+
+// TestItamae_ParallelInvalidErrorStrategy tests that unknown strategies fail the node.
+func TestItamae_ParallelInvalidErrorStrategy(t *testing.T) {
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+
+	_, err := chef.Serve(context.Background(), parallelBento("ignore", sleepy("ok", "0s", false)))
+	if err == nil || !strings.Contains(err.Error(), "errorStrategy") {
+		t.Errorf("error = %v, want an errorStrategy error", err)
+	}
+}
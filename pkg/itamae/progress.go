@@ -22,7 +22,7 @@ type executionGraph struct {
 
 // nodeState tracks runtime state for a single node.
 type nodeState struct {
-	State    string // "pending" | "executing" | "completed" | "error"
+	State    string // "pending" | "executing" | "completed" | "skipped" | "error"
 	Progress int    // 0-100
 	Message  string
 }
@@ -59,9 +59,13 @@ func analyzeNode(def *neta.Definition, graph *executionGraph, level int) {
 }
 
 // analyzeGroupNode flattens group children (groups are transparent in progress graph).
+// Catch, onCancel and finally nodes count too; unused catch and onCancel
+// nodes are reported as skipped.
 func analyzeGroupNode(def *neta.Definition, graph *executionGraph, level int) {
-	for i := range def.Nodes {
-		analyzeNode(&def.Nodes[i], graph, level)
+	for _, nodes := range [][]neta.Definition{def.Nodes, def.Catch, def.OnCancel, def.Finally} {
+		for i := range nodes {
+			analyzeNode(&nodes[i], graph, level)
+		}
 	}
 }
 
@@ -117,6 +121,9 @@ const (
 
 	// WeightTransform is low for in-memory data transformation.
 	WeightTransform = 50
+
+	// WeightBranch is low because if/switch only evaluate an expression.
+	WeightBranch = 50
 )
 
 // getNodeWeight returns the weight for a node type based on typical execution duration.
@@ -131,6 +138,8 @@ func getNodeWeight(nodeType string) int {
 		"file-system":   WeightFileSystem,
 		"edit-fields":   WeightEditFields,
 		"transform":     WeightTransform,
+		"if":            WeightBranch,
+		"switch":        WeightBranch,
 	}
 	if w, ok := weights[nodeType]; ok {
 		return w
@@ -210,7 +219,7 @@ func (s *executionState) calculateProgressUnsafe() int {
 		state := s.nodes[nodeID]
 
 		switch state.State {
-		case "completed":
+		case "completed", "skipped":
 			completedWeight += graphNode.Weight
 
 		case "executing":
@@ -0,0 +1,167 @@
+package itamae
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// resourcePools holds the named concurrency limits of a run, declared in
+// the bento's resources (e.g. {"blender": 1, "network": 8}).
+//
+// A node naming a resource in uses holds one of its slots while it
+// executes, whichever loop, parallel branch or sub-bento it runs in, so
+// the limit applies to the whole run rather than to one container.
+type resourcePools struct {
+	mu    sync.Mutex
+	pools map[string]chan struct{}
+}
+
+// newResourcePools creates the pools declared by a bento.
+func newResourcePools(limits map[string]int) *resourcePools {
+	r := &resourcePools{pools: make(map[string]chan struct{})}
+	r.declare(limits)
+	return r
+}
+
+// declare adds pools for resources not declared yet. A sub-bento declaring
+// a resource its caller already declared shares the caller's pool.
+func (r *resourcePools) declare(limits map[string]int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, limit := range limits {
+		if _, ok := r.pools[name]; !ok {
+			r.pools[name] = make(chan struct{}, max(limit, 1))
+		}
+	}
+}
+
+// pool returns the slots of a declared resource.
+func (r *resourcePools) pool(name string) (chan struct{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pool, ok := r.pools[name]
+	return pool, ok
+}
+
+// heldResourcesKey is the context key listing the resources held by the
+// nodes a node runs inside.
+type heldResourcesKey struct{}
+
+// holdsResource reports whether an enclosing node already holds name.
+func holdsResource(ctx context.Context, name string) bool {
+	held, _ := ctx.Value(heldResourcesKey{}).([]string)
+	return slices.Contains(held, name)
+}
+
+// resourceSlotKey is the context key of the resource slot a node holds.
+type resourceSlotKey struct{}
+
+// resourceSlot is a resource slot held by a node. A node waiting to retry
+// gives it up, so others can use the resource in the meantime.
+type resourceSlot struct {
+	mu       sync.Mutex
+	nodeID   string
+	resource string
+	pool     chan struct{}
+	held     bool
+	done     bool // The node finished; the slot is not taken again
+}
+
+// release gives the slot back if it is held.
+func (s *resourceSlot) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.held {
+		<-s.pool
+		s.held = false
+	}
+}
+
+// finish releases the slot for good once the node is done.
+func (s *resourceSlot) finish() {
+	s.release()
+	s.mu.Lock()
+	s.done = true
+	s.mu.Unlock()
+}
+
+// ownSlot returns the resource slot def holds itself (not one of an
+// enclosing node), or nil.
+func ownSlot(ctx context.Context, def *neta.Definition) *resourceSlot {
+	slot, _ := ctx.Value(resourceSlotKey{}).(*resourceSlot)
+	if slot == nil || slot.nodeID != def.ID {
+		return nil
+	}
+	return slot
+}
+
+// reacquireSlot takes back a slot given up while waiting to retry.
+func (i *Itamae) reacquireSlot(ctx context.Context, slot *resourceSlot) error {
+	if err := i.waitForSlot(ctx, slot.nodeID, slot.resource, slot.pool); err != nil {
+		return err
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if slot.done {
+		<-slot.pool
+		return nil
+	}
+	slot.held = true
+	return nil
+}
+
+// acquireResource waits for a slot of the resource def uses, if any, and
+// returns ctx marked as holding it along with the function releasing it.
+// Nodes inside one holding the resource run on its slot instead of waiting
+// for another. No node starts once the run is stopping, so neither does
+// the wait.
+func (i *Itamae) acquireResource(ctx context.Context, def *neta.Definition) (context.Context, func(), error) {
+	if def.Uses == "" || holdsResource(ctx, def.Uses) {
+		return ctx, func() {}, nil
+	}
+
+	pool, ok := i.resources.pool(def.Uses)
+	if !ok {
+		return ctx, nil, newNodeError(def.ID, def.Type, "acquire resource",
+			fmt.Errorf("resource '%s' is not declared in the bento's resources", def.Uses))
+	}
+	if err := i.waitForSlot(ctx, def.ID, def.Uses, pool); err != nil {
+		return ctx, nil, err
+	}
+
+	slot := &resourceSlot{nodeID: def.ID, resource: def.Uses, pool: pool, held: true}
+	held, _ := ctx.Value(heldResourcesKey{}).([]string)
+	held = append(slices.Clip(held), def.Uses)
+	ctx = context.WithValue(ctx, heldResourcesKey{}, held)
+	return context.WithValue(ctx, resourceSlotKey{}, slot), slot.finish, nil
+}
+
+// waitForSlot takes a slot of pool, waiting until one is free unless ctx
+// is done or the run is stopping.
+func (i *Itamae) waitForSlot(ctx context.Context, nodeID, resource string, pool chan struct{}) error {
+	select {
+	case pool <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if i.logger != nil {
+		i.logger.Debug("Waiting for resource", "node_id", nodeID, "resource", resource)
+	}
+	select {
+	case pool <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-runContext(ctx).Done():
+		return runContext(ctx).Err()
+	}
+}
@@ -0,0 +1,190 @@
+package itamae_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// busyNeta keeps busy for a while and records the most nodes busy at once.
+type busyNeta struct {
+	mu      *sync.Mutex
+	running *int
+	peak    *int
+}
+
+func (b *busyNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	*b.running++
+	*b.peak = max(*b.peak, *b.running)
+	b.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	b.mu.Lock()
+	*b.running--
+	b.mu.Unlock()
+	return map[string]interface{}{}, nil
+}
+
+// newBusyChef creates an itamae with the busy neta registered, and returns
+// a function reporting the most busy nodes at once.
+func newBusyChef() (*itamae.Itamae, func() int) {
+	var mu sync.Mutex
+	var running, peak int
+	p := pantry.New()
+	p.RegisterFactory("busy", func() neta.Executable {
+		return &busyNeta{mu: &mu, running: &running, peak: &peak}
+	})
+
+	return itamae.New(p, nil), func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return peak
+	}
+}
+
+// renderLoop builds a forEach loop running three busy children at a time.
+func renderLoop(id, uses string) neta.Definition {
+	return neta.Definition{
+		ID:   id,
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode":           "forEach",
+			"items":          []interface{}{"A", "B", "C", "D"},
+			"maxConcurrency": float64(3),
+		},
+		Nodes: []neta.Definition{{ID: id + "-render", Type: "busy", Uses: uses}},
+	}
+}
+
+// TestItamae_Resources tests that a resource limits nodes across loops and parallel branches.
+func TestItamae_Resources(t *testing.T) {
+	tests := []struct {
+		uses string
+		want int
+	}{
+		{uses: "blender", want: 1},
+		{uses: "network", want: 2},
+		{uses: "", want: 6}, // Only each loop's maxConcurrency applies
+	}
+
+	for _, tt := range tests {
+		chef, peak := newBusyChef()
+		_, err := chef.Serve(context.Background(), &neta.Definition{
+			ID:        "renders",
+			Type:      "parallel",
+			Resources: map[string]int{"blender": 1, "network": 2},
+			Nodes:     []neta.Definition{renderLoop("products", tt.uses), renderLoop("variants", tt.uses)},
+		})
+		if err != nil {
+			t.Fatalf("uses %q: Serve failed: %v", tt.uses, err)
+		}
+		if got := peak(); got != tt.want {
+			t.Errorf("uses %q: %d nodes busy at once, want %d", tt.uses, got, tt.want)
+		}
+	}
+}
+
+// TestItamae_ResourcesNested tests that nodes inside one holding a resource
+// use its slot instead of waiting for another.
+func TestItamae_ResourcesNested(t *testing.T) {
+	chef, _ := newBusyChef()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := chef.Serve(context.Background(), &neta.Definition{
+			ID:        "scene",
+			Type:      "group",
+			Resources: map[string]int{"blender": 1},
+			Uses:      "blender",
+			Nodes:     []neta.Definition{{ID: "render", Type: "busy", Uses: "blender"}},
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Serve failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Nested node waited for the resource its group holds")
+	}
+}
+
+// TestItamae_ResourcesUndeclared tests that using an undeclared resource fails the node.
+func TestItamae_ResourcesUndeclared(t *testing.T) {
+	chef, _ := newBusyChef()
+
+	_, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:    "scene",
+		Type:  "group",
+		Nodes: []neta.Definition{{ID: "render", Type: "busy", Uses: "gpu"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "resource 'gpu' is not declared") {
+		t.Errorf("error = %v, want an undeclared resource error", err)
+	}
+}
+
+// TestItamae_ResourcesCancelled tests that a node waiting for a resource
+// doesn't start once the run is cancelled.
+func TestItamae_ResourcesCancelled(t *testing.T) {
+	chef, _ := newBusyChef()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := chef.Serve(ctx, &neta.Definition{
+		ID:        "renders",
+		Type:      "parallel",
+		Resources: map[string]int{"blender": 1},
+		Nodes: []neta.Definition{
+			{ID: "first", Type: "busy", Uses: "blender"},
+			{ID: "second", Type: "busy", Uses: "blender"},
+			{ID: "third", Type: "busy", Uses: "blender"},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected error from the cancelled run")
+	}
+	if elapsed := time.Since(start); elapsed >= 30*time.Millisecond {
+		t.Errorf("Serve took %v, waiting nodes still ran one after another", elapsed)
+	}
+}
+
+// TestItamae_ResourcesReleasedForRetry tests that a node waiting to retry
+// lets other nodes use its resource.
+func TestItamae_ResourcesReleasedForRetry(t *testing.T) {
+	calls := 0
+	p := newSleepyPantry(make(chan struct{}, 2))
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: &calls, failures: 1, output: map[string]interface{}{"ok": true}}
+	})
+
+	// The run stops while fetch waits to retry; render must have run by then
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	result, _ := itamae.New(p, nil).Serve(ctx, &neta.Definition{
+		ID:         "scene",
+		Type:       "group",
+		Parameters: map[string]interface{}{"maxConcurrency": float64(2)},
+		Resources:  map[string]int{"blender": 1},
+		Nodes: []neta.Definition{
+			{ID: "fetch", Type: "flaky", Uses: "blender", Retry: &neta.RetryPolicy{MaxAttempts: 2, Backoff: "5s"}},
+			{ID: "wait", Type: "sleepy", Parameters: map[string]interface{}{"delay": "50ms"}},
+			{ID: "render", Type: "sleepy", Uses: "blender", Parameters: map[string]interface{}{"delay": "0s"}},
+		},
+		Edges: []neta.Edge{{ID: "e1", Source: "wait", Target: "render"}},
+	})
+
+	if _, ok := result.NodeOutputs["render"]; !ok {
+		t.Error("render waited for the resource held by the node waiting to retry")
+	}
+}
@@ -0,0 +1,158 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// flakyNeta fails until it has been called failures+1 times.
+type flakyNeta struct {
+	calls    *int
+	failures int
+	cause    error // Wrapped by failures (default: connection reset)
+	output   map[string]interface{}
+}
+
+func (f *flakyNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	*f.calls++
+	if *f.calls <= f.failures && f.cause != nil {
+		return nil, fmt.Errorf("request failed (call %d): %w", *f.calls, f.cause)
+	}
+	if *f.calls <= f.failures {
+		return nil, fmt.Errorf("connection reset (call %d)", *f.calls)
+	}
+	return f.output, nil
+}
+
+// retryRecorder records retry events sent to the messenger.
+type retryRecorder struct {
+	attempts []int
+}
+
+func (r *retryRecorder) SendNodeStarted(path, name, nodeType string)                      {}
+func (r *retryRecorder) SendNodeCompleted(path string, duration time.Duration, err error) {}
+func (r *retryRecorder) SendLoopChild(loopPath, childName string, index, total int)       {}
+func (r *retryRecorder) SendNodeSkipped(path, name, nodeType string)                      {}
+func (r *retryRecorder) SendNodeCached(path string)                                       {}
+func (r *retryRecorder) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	r.attempts = append(r.attempts, attempt)
+}
+
+// runRetryNode executes a single flaky node with the given retry policy.
+func runRetryNode(t *testing.T, retry *neta.RetryPolicy, failures int, output map[string]interface{}) (int, *retryRecorder, error) {
+	t.Helper()
+
+	calls := 0
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: &calls, failures: failures, output: output}
+	})
+
+	recorder := &retryRecorder{}
+	chef := itamae.NewWithMessenger(p, nil, recorder)
+	_, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:    "fetch",
+		Type:  "flaky",
+		Name:  "Fetch Overlay",
+		Retry: retry,
+	})
+	return calls, recorder, err
+}
+
+// TestItamae_RetrySucceeds tests that a node succeeds after transient failures.
+func TestItamae_RetrySucceeds(t *testing.T) {
+	retry := &neta.RetryPolicy{MaxAttempts: 3, Backoff: "1ms"}
+
+	calls, recorder, err := runRetryNode(t, retry, 2, map[string]interface{}{"ok": true})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if fmt.Sprint(recorder.attempts) != "[2 3]" {
+		t.Errorf("reported attempts = %v, want [2 3]", recorder.attempts)
+	}
+}
+
+// TestItamae_RetryExhausted tests that the last error is returned after maxAttempts.
+func TestItamae_RetryExhausted(t *testing.T) {
+	retry := &neta.RetryPolicy{MaxAttempts: 2, Backoff: "1ms"}
+
+	calls, _, err := runRetryNode(t, retry, 5, nil)
+	if err == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if !strings.Contains(err.Error(), "failed after 2 attempts") {
+		t.Errorf("Error should mention attempts: %v", err)
+	}
+}
+
+// TestItamae_RetryOnPattern tests that non-matching errors are not retried.
+func TestItamae_RetryOnPattern(t *testing.T) {
+	retry := &neta.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     "1ms",
+		RetryOn:     &neta.RetryOn{ErrorPattern: "timeout"},
+	}
+
+	calls, _, err := runRetryNode(t, retry, 1, nil)
+	if err == nil {
+		t.Fatal("Expected error for non-retryable failure")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+// TestItamae_RetryOnStatusCode tests that listed status codes are retried.
+func TestItamae_RetryOnStatusCode(t *testing.T) {
+	retry := &neta.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     "1ms",
+		RetryOn:     &neta.RetryOn{StatusCodes: []int{503}},
+	}
+
+	calls, _, err := runRetryNode(t, retry, 0, map[string]interface{}{"statusCode": 503})
+	if err == nil {
+		t.Fatal("Expected error when status code stays retryable")
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if !strings.Contains(err.Error(), "503") {
+		t.Errorf("Error should mention status code: %v", err)
+	}
+}
+
+// TestItamae_RetryClientTimeout tests that a neta's own timeout is retried
+// while the node's context is still running.
+func TestItamae_RetryClientTimeout(t *testing.T) {
+	calls := 0
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: &calls, failures: 1, cause: context.DeadlineExceeded, output: map[string]interface{}{"ok": true}}
+	})
+
+	_, err := itamae.New(p, nil).Serve(context.Background(), &neta.Definition{
+		ID:    "fetch",
+		Type:  "flaky",
+		Retry: &neta.RetryPolicy{MaxAttempts: 2, Backoff: "1ms"},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,175 @@
+package itamae_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/omakase"
+)
+
+// memoryLoader is an in-memory BentoLoader.
+type memoryLoader map[string]*neta.Definition
+
+func (m memoryLoader) LoadBento(ctx context.Context, name string) (*neta.Definition, error) {
+	def, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("bento '%s' not found", name)
+	}
+	return def, nil
+}
+
+// TestItamae_SubBentoByPath tests inputs and declared outputs of a file sub-bento.
+func TestItamae_SubBentoByPath(t *testing.T) {
+	dir := t.TempDir()
+	child := `{
+		"id": "setup-folder", "type": "group", "name": "Setup Product Folder",
+		"nodes": [{"id": "mkdir", "type": "sleepy", "name": "Make Folder",
+			"parameters": {"value": "products/{{.product}}"}}],
+		"outputs": {"folder": "{{.mkdir.value}}"}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "setup.bento.json"), []byte(child), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+	chef.SetBentoDir(dir)
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "setup",
+		Type: "bento",
+		Name: "Setup",
+		Parameters: map[string]interface{}{
+			"path":   "setup.bento.json",
+			"inputs": map[string]interface{}{"product": "chair"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	output := result.NodeOutputs["setup"].(map[string]interface{})
+	if output["folder"] != "products/chair" {
+		t.Errorf("folder = %v, want products/chair", output["folder"])
+	}
+	if _, ok := output["mkdir"]; ok {
+		t.Error("Undeclared sub-bento outputs should not be returned")
+	}
+}
+
+// TestItamae_SubBentoRecursion tests that a bento calling itself is stopped.
+func TestItamae_SubBentoRecursion(t *testing.T) {
+	self := &neta.Definition{
+		ID:         "again",
+		Type:       "bento",
+		Name:       "Again",
+		Parameters: map[string]interface{}{"bento": "forever"},
+	}
+
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+	chef.SetBentoLoader(memoryLoader{"forever": self})
+	_, err := chef.Serve(context.Background(), self)
+	if err == nil {
+		t.Fatal("Expected error for recursive sub-bento")
+	}
+	if !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("Error should mention nesting depth: %v", err)
+	}
+}
+
+// TestItamae_SubBentoInLoop tests a sub-bento running as a loop child.
+func TestItamae_SubBentoInLoop(t *testing.T) {
+	setup := &neta.Definition{
+		ID:      "setup-folder",
+		Type:    "group",
+		Nodes:   []neta.Definition{{ID: "mkdir", Type: "sleepy", Parameters: map[string]interface{}{"value": "products/{{.product}}"}}},
+		Outputs: map[string]interface{}{"folder": "{{.mkdir.value}}"},
+	}
+
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+	chef.SetBentoLoader(memoryLoader{"setup": setup})
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "products",
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode":  "forEach",
+			"items": []interface{}{"chair", "table"},
+		},
+		Nodes: []neta.Definition{{
+			ID:   "setup",
+			Type: "bento",
+			Parameters: map[string]interface{}{
+				"bento":  "setup",
+				"inputs": map[string]interface{}{"product": "{{.item}}"},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	for idx, want := range []string{"products/chair", "products/table"} {
+		iteration := result.NodeOutputs["products"].([]interface{})[idx].(map[string]interface{})
+		if folder := iteration["setup"].(map[string]interface{})["folder"]; folder != want {
+			t.Errorf("iteration %d: folder = %v, want %s", idx, folder, want)
+		}
+	}
+}
+
+// TestItamae_SubBentoVariables tests that declared variables of a sub-bento
+// take their defaults when the caller passes no input for them.
+func TestItamae_SubBentoVariables(t *testing.T) {
+	render := &neta.Definition{
+		ID:        "render",
+		Type:      "group",
+		Variables: []neta.Variable{{Name: "size", Type: "integer", Default: float64(512)}, {Name: "format", Default: "png"}},
+		Nodes:     []neta.Definition{{ID: "out", Type: "sleepy", Parameters: map[string]interface{}{"value": "{{.size}}.{{.format}}"}}},
+		Outputs:   map[string]interface{}{"file": "{{.out.value}}"},
+	}
+
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+	chef.SetBentoLoader(memoryLoader{"render": render})
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "call",
+		Type: "bento",
+		Parameters: map[string]interface{}{
+			"bento":  "render",
+			"inputs": map[string]interface{}{"format": "jpg"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if file := result.NodeOutputs["call"].(map[string]interface{})["file"]; file != "512.jpg" {
+		t.Errorf("file = %v, want 512.jpg", file)
+	}
+}
+
+// TestItamae_SubBentoInvalid tests that a sub-bento failing validation doesn't run.
+func TestItamae_SubBentoInvalid(t *testing.T) {
+	invalid := &neta.Definition{
+		ID:    "render",
+		Type:  "group",
+		Nodes: []neta.Definition{{ID: "out", Type: "sleepy"}},
+	}
+
+	chef := itamae.New(newSleepyPantry(make(chan struct{}, 1)), nil)
+	chef.SetBentoLoader(memoryLoader{"render": invalid})
+	chef.SetBentoValidator(omakase.New())
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:         "call",
+		Type:       "bento",
+		Parameters: map[string]interface{}{"bento": "render"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid sub-bento") {
+		t.Fatalf("error = %v, want an invalid sub-bento error", err)
+	}
+	if _, ok := result.NodeOutputs["out"]; ok {
+		t.Error("Node of the invalid sub-bento ran")
+	}
+}
@@ -0,0 +1,107 @@
+package itamae_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// hungNeta blocks for a long time unless cancelled.
+type hungNeta struct{}
+
+func (h *hungNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	select {
+	case <-time.After(5 * time.Second):
+		return map[string]interface{}{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cleanupNeta takes a while to stop once cancelled, then records that it stopped.
+type cleanupNeta struct {
+	stopped *atomic.Bool
+}
+
+func (c *cleanupNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	c.stopped.Store(true)
+	return nil, ctx.Err()
+}
+
+// newHungPantry creates a pantry with the hung neta registered.
+func newHungPantry() *pantry.Pantry {
+	p := pantry.New()
+	p.RegisterFactory("hung", func() neta.Executable { return &hungNeta{} })
+	return p
+}
+
+// TestItamae_NodeTimeoutWaitsForNeta tests that a timed-out node ends only
+// once its neta has stopped, so nothing keeps running behind the run's back.
+func TestItamae_NodeTimeoutWaitsForNeta(t *testing.T) {
+	var stopped atomic.Bool
+	p := pantry.New()
+	p.RegisterFactory("cleanup", func() neta.Executable { return &cleanupNeta{stopped: &stopped} })
+
+	def := &neta.Definition{ID: "render", Type: "cleanup", Timeout: "10ms"}
+	if _, err := itamae.New(p, nil).Serve(context.Background(), def); err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	if !stopped.Load() {
+		t.Error("Serve returned while the timed-out neta was still running")
+	}
+}
+
+// TestItamae_NodeTimeout tests that a hung neta is cancelled at its timeout.
+func TestItamae_NodeTimeout(t *testing.T) {
+	def := &neta.Definition{ID: "render", Type: "hung", Timeout: "50ms"}
+
+	start := time.Now()
+	_, err := itamae.New(newHungPantry(), nil).Serve(context.Background(), def)
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Serve took %v, timeout was not enforced", elapsed)
+	}
+
+	var timeoutErr *itamae.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError, got %T: %v", err, err)
+	}
+	if timeoutErr.NodeID != "render" || timeoutErr.Limit != 50*time.Millisecond {
+		t.Errorf("TimeoutError = %+v, want render/50ms", timeoutErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("TimeoutError should unwrap to context.DeadlineExceeded")
+	}
+}
+
+// TestItamae_GroupTimeout tests that a group's timeout covers its children.
+func TestItamae_GroupTimeout(t *testing.T) {
+	def := &neta.Definition{
+		ID:      "renders",
+		Type:    "group",
+		Timeout: "50ms",
+		Nodes: []neta.Definition{
+			{ID: "render", Type: "hung"},
+		},
+	}
+
+	_, err := itamae.New(newHungPantry(), nil).Serve(context.Background(), def)
+
+	var timeoutErr *itamae.TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected *TimeoutError, got %T: %v", err, err)
+	}
+	if timeoutErr.NodeID != "renders" {
+		t.Errorf("NodeID = %q, want %q", timeoutErr.NodeID, "renders")
+	}
+}
@@ -0,0 +1,206 @@
+package itamae
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Span kinds.
+const (
+	SpanNode      = "node"      // A node of any type (group, loop, leaf, ...)
+	SpanIteration = "iteration" // One loop iteration
+	SpanAttempt   = "attempt"   // One attempt of a node with a retry policy
+)
+
+// Span is a timed unit of work in a run.
+// Spans are reported to the Tracer when they end, children before parents.
+type Span struct {
+	ID         string // Unique within the process (16 hex digits)
+	ParentID   string // Empty for the root node
+	Kind       string
+	Name       string
+	NodeID     string
+	NodeType   string
+	Attributes map[string]interface{}
+	Start      time.Time
+	End        time.Time
+	Err        error
+}
+
+// Tracer receives finished spans (e.g. to export a trace file).
+// Must be safe for concurrent use (concurrent branches and iterations).
+type Tracer interface {
+	RecordSpan(span Span)
+}
+
+// SetTracer enables span recording for subsequent runs.
+func (i *Itamae) SetTracer(tracer Tracer) {
+	i.tracer = tracer
+}
+
+// spanSeq generates span IDs (shared by sub-bento chefs).
+var spanSeq atomic.Uint64
+
+// spanKey is the context key of the current span ID.
+type spanKey struct{}
+
+// startSpan starts a span as a child of the span in ctx.
+// Returns ctx carrying the new span, or ctx unchanged (and a nil span)
+// when tracing is disabled.
+func (i *Itamae) startSpan(ctx context.Context, kind, name string, def *neta.Definition) (context.Context, *Span) {
+	if i.tracer == nil {
+		return ctx, nil
+	}
+
+	parentID, _ := ctx.Value(spanKey{}).(string)
+	span := &Span{
+		ID:         fmt.Sprintf("%016x", spanSeq.Add(1)),
+		ParentID:   parentID,
+		Kind:       kind,
+		Name:       name,
+		NodeID:     def.ID,
+		NodeType:   def.Type,
+		Attributes: make(map[string]interface{}),
+		Start:      time.Now(),
+	}
+	return context.WithValue(ctx, spanKey{}, span.ID), span
+}
+
+// startNodeSpan starts the span of a node, with its parameters (secrets
+// stripped) as attributes.
+func (i *Itamae) startNodeSpan(ctx context.Context, def *neta.Definition, execCtx *executionContext) (context.Context, *Span) {
+	ctx, span := i.startSpan(ctx, SpanNode, def.Name, def)
+	if span != nil && len(def.Parameters) > 0 {
+		span.Attributes["params"] = traceParams(def.Parameters, execCtx.withoutVariables())
+	}
+	return ctx, span
+}
+
+// startIterationSpan starts the span of one loop iteration.
+func (i *Itamae) startIterationSpan(ctx context.Context, def *neta.Definition, idx, total int) (context.Context, *Span) {
+	ctx, span := i.startSpan(ctx, SpanIteration, fmt.Sprintf("%s [%d]", def.Name, idx), def)
+	if span != nil {
+		span.Attributes["index"] = idx
+		span.Attributes["total"] = total
+	}
+	return ctx, span
+}
+
+// startAttemptSpan starts the span of one attempt of a node with a retry
+// policy. Nodes without retries get no attempt spans.
+func (i *Itamae) startAttemptSpan(ctx context.Context, def *neta.Definition, attempt, maxAttempts int) *Span {
+	if maxAttempts < 2 {
+		return nil
+	}
+	_, span := i.startSpan(ctx, SpanAttempt, fmt.Sprintf("%s attempt %d", def.Name, attempt), def)
+	if span != nil {
+		span.Attributes["attempt"] = attempt
+		span.Attributes["maxAttempts"] = maxAttempts
+	}
+	return span
+}
+
+// endSpan ends a span and reports it. Safe to call with a nil span.
+func (i *Itamae) endSpan(span *Span, err error) {
+	if span == nil {
+		return
+	}
+	span.End = time.Now()
+	span.Err = err
+	i.tracer.RecordSpan(*span)
+}
+
+// sensitiveKeys are parts of parameter names (matched case-insensitively)
+// whose values are never written to a trace, as they usually hold
+// credentials even when written literally.
+var sensitiveKeys = []string{"header", "token", "password", "apikey", "api_key", "authorization", "secret", "cookie"}
+
+// redacted replaces sensitive values in traces.
+const redacted = "[redacted]"
+
+// traceParams resolves parameters for a trace without their secrets.
+// execCtx holds no variables, environment or secrets (see withoutVariables),
+// so values referencing any of them keep the unresolved template wherever
+// they appear (URLs, args, bodies). Values of sensitive keys are redacted.
+func traceParams(params map[string]interface{}, execCtx *executionContext) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if isSensitiveKey(k) {
+			resolved[k] = redactValue(v)
+			continue
+		}
+		resolved[k] = traceValue(v, execCtx)
+	}
+	return resolved
+}
+
+// isSensitiveKey reports whether a parameter's value must not be traced.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue hides a sensitive value. Maps (e.g. headers) keep their keys.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		hidden := make(map[string]interface{}, len(v))
+		for k := range v {
+			hidden[k] = redacted
+		}
+		return hidden
+	case map[string]string:
+		hidden := make(map[string]interface{}, len(v))
+		for k := range v {
+			hidden[k] = redacted
+		}
+		return hidden
+	default:
+		return redacted
+	}
+}
+
+// expandsEnv reports whether s references a set environment variable as
+// $NAME or ${NAME}, which parameter resolution expands along with paths.
+func expandsEnv(s string) bool {
+	found := false
+	os.Expand(s, func(name string) string {
+		if _, ok := os.LookupEnv(name); ok {
+			found = true
+		}
+		return ""
+	})
+	return found
+}
+
+// traceValue resolves one parameter value, leaving secret templates unresolved.
+func traceValue(value interface{}, execCtx *executionContext) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "{{SECRETS.") || expandsEnv(v) {
+			return v
+		}
+		return execCtx.resolveValue(v)
+	case map[string]interface{}:
+		return traceParams(v, execCtx)
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for idx, item := range v {
+			resolved[idx] = traceValue(item, execCtx)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
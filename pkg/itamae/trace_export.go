@@ -0,0 +1,233 @@
+package itamae
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TraceRecorder is a Tracer that keeps all spans in memory for export.
+type TraceRecorder struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+// NewTraceRecorder creates an empty trace recorder.
+func NewTraceRecorder() *TraceRecorder {
+	return &TraceRecorder{}
+}
+
+// RecordSpan stores a finished span.
+func (r *TraceRecorder) RecordSpan(span Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+// Spans returns the recorded spans ordered by start time.
+func (r *TraceRecorder) Spans() []Span {
+	r.mu.Lock()
+	spans := append([]Span{}, r.spans...)
+	r.mu.Unlock()
+
+	sort.SliceStable(spans, func(a, b int) bool {
+		if spans[a].Start.Equal(spans[b].Start) {
+			return spans[a].End.After(spans[b].End) // Parents before children
+		}
+		return spans[a].Start.Before(spans[b].Start)
+	})
+	return spans
+}
+
+// chromeEvent is a complete ("X") event in the Chrome trace event format.
+type chromeEvent struct {
+	Name string                 `json:"name"`
+	Cat  string                 `json:"cat"`
+	Ph   string                 `json:"ph"`
+	Ts   int64                  `json:"ts"`  // Microseconds since the trace start
+	Dur  int64                  `json:"dur"` // Microseconds
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the spans in Chrome trace event format
+// (loadable in Perfetto and chrome://tracing).
+//
+// Overlapping spans that don't nest (concurrent branches and iterations)
+// are placed on separate tracks.
+func (r *TraceRecorder) WriteChromeTrace(w io.Writer) error {
+	spans := r.Spans()
+	events := make([]chromeEvent, 0, len(spans))
+	if len(spans) > 0 {
+		origin := spans[0].Start
+		tracks := assignTracks(spans)
+		for idx, span := range spans {
+			events = append(events, chromeEvent{
+				Name: span.Name,
+				Cat:  span.Kind,
+				Ph:   "X",
+				Ts:   span.Start.Sub(origin).Microseconds(),
+				Dur:  span.End.Sub(span.Start).Microseconds(),
+				Pid:  1,
+				Tid:  tracks[idx],
+				Args: chromeArgs(span),
+			})
+		}
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
+
+// chromeArgs returns the args shown for a span in the trace viewer.
+func chromeArgs(span Span) map[string]interface{} {
+	args := make(map[string]interface{}, len(span.Attributes)+3)
+	for k, v := range span.Attributes {
+		args[k] = v
+	}
+	args["nodeId"] = span.NodeID
+	args["nodeType"] = span.NodeType
+	if span.Err != nil {
+		args["error"] = span.Err.Error()
+	}
+	return args
+}
+
+// assignTracks assigns each span (sorted by start) to a track such that spans
+// on one track are properly nested. Spans stay on their parent's track when
+// they fit, so sequential work reads as a call stack.
+func assignTracks(spans []Span) []int {
+	var stacks [][]time.Time // Per track: end times of the open spans
+	trackOf := make(map[string]int, len(spans))
+	tracks := make([]int, len(spans))
+
+	fits := func(track int, span Span) bool {
+		stack := stacks[track]
+		for len(stack) > 0 && !stack[len(stack)-1].After(span.Start) {
+			stack = stack[:len(stack)-1]
+		}
+		stacks[track] = stack
+		return len(stack) == 0 || !span.End.After(stack[len(stack)-1])
+	}
+
+	for idx, span := range spans {
+		track := -1
+		if parent, ok := trackOf[span.ParentID]; ok && fits(parent, span) {
+			track = parent
+		}
+		for t := 0; track < 0 && t < len(stacks); t++ {
+			if fits(t, span) {
+				track = t
+			}
+		}
+		if track < 0 {
+			track = len(stacks)
+			stacks = append(stacks, nil)
+		}
+
+		stacks[track] = append(stacks[track], span.End)
+		trackOf[span.ID] = track
+		tracks[idx] = track + 1 // Thread IDs start at 1
+	}
+	return tracks
+}
+
+// WriteOTLP writes the spans as an OTLP/JSON trace export request
+// (the format accepted by OpenTelemetry collectors at /v1/traces).
+func (r *TraceRecorder) WriteOTLP(w io.Writer, serviceName string) error {
+	traceID := make([]byte, 16)
+	if _, err := rand.Read(traceID); err != nil {
+		return fmt.Errorf("failed to generate trace ID: %w", err)
+	}
+
+	spans := r.Spans()
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, otlpSpan(span, hex.EncodeToString(traceID)))
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []interface{}{otlpAttribute("service.name", serviceName)},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "bento"},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	})
+}
+
+// otlpSpan converts a span to its OTLP/JSON form.
+func otlpSpan(span Span, traceID string) map[string]interface{} {
+	attributes := []interface{}{
+		otlpAttribute("bento.span.kind", span.Kind),
+		otlpAttribute("bento.node.id", span.NodeID),
+		otlpAttribute("bento.neta.type", span.NodeType),
+	}
+	keys := make([]string, 0, len(span.Attributes))
+	for k := range span.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attributes = append(attributes, otlpAttribute("bento."+k, span.Attributes[k]))
+	}
+
+	status := map[string]interface{}{"code": 1} // STATUS_CODE_OK
+	if span.Err != nil {
+		status = map[string]interface{}{"code": 2, "message": span.Err.Error()} // STATUS_CODE_ERROR
+	}
+
+	out := map[string]interface{}{
+		"traceId":           traceID,
+		"spanId":            span.ID,
+		"name":              span.Name,
+		"kind":              1, // SPAN_KIND_INTERNAL
+		"startTimeUnixNano": strconv.FormatInt(span.Start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(span.End.UnixNano(), 10),
+		"attributes":        attributes,
+		"status":            status,
+	}
+	if span.ParentID != "" {
+		out["parentSpanId"] = span.ParentID
+	}
+	return out
+}
+
+// otlpAttribute converts a key/value pair to an OTLP attribute.
+// Values other than strings, numbers and booleans are JSON encoded.
+func otlpAttribute(key string, value interface{}) map[string]interface{} {
+	var v map[string]interface{}
+	switch val := value.(type) {
+	case string:
+		v = map[string]interface{}{"stringValue": val}
+	case bool:
+		v = map[string]interface{}{"boolValue": val}
+	case int:
+		v = map[string]interface{}{"intValue": strconv.Itoa(val)}
+	case float64:
+		v = map[string]interface{}{"doubleValue": val}
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			data = []byte(fmt.Sprint(val))
+		}
+		v = map[string]interface{}{"stringValue": string(data)}
+	}
+	return map[string]interface{}{"key": key, "value": v}
+}
@@ -0,0 +1,182 @@
+package itamae_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// traceBento builds a bento with a forEach loop and a node with retries.
+func traceBento() *neta.Definition {
+	return &neta.Definition{
+		ID:   "trace-bento",
+		Type: "group",
+		Name: "Trace",
+		Nodes: []neta.Definition{
+			{
+				ID:   "renders",
+				Type: "loop",
+				Name: "Renders",
+				Parameters: map[string]interface{}{
+					"mode":  "forEach",
+					"items": []interface{}{"chair", "table"},
+				},
+				Nodes: []neta.Definition{
+					{
+						ID:   "render",
+						Type: "render",
+						Name: "Render",
+						Parameters: map[string]interface{}{
+							"values": map[string]interface{}{
+								"product": "{{.item}}",
+								"token":   "{{SECRETS.API_KEY}}",
+								"note":    "{{SECRETS.API_KEY}}",
+							},
+							"headers": map[string]interface{}{
+								"Authorization": "Bearer {{.FIGMA_TOKEN}}",
+							},
+						},
+					},
+				},
+			},
+			{
+				ID:    "upload",
+				Type:  "flaky",
+				Name:  "Upload",
+				Retry: &neta.RetryPolicy{MaxAttempts: 3, Backoff: "1ms"},
+			},
+		},
+		Edges: []neta.Edge{{ID: "e1", Source: "renders", Target: "upload"}},
+	}
+}
+
+// TestItamae_TraceSpans tests spans for nodes, iterations and retry attempts.
+func TestItamae_TraceSpans(t *testing.T) {
+	attempts := 0
+	p := pantry.New()
+	calls := make(map[string][]map[string]interface{})
+	p.RegisterFactory("render", func() neta.Executable { return &recordNeta{nodeType: "render", calls: calls} })
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: &attempts, failures: 1, output: map[string]interface{}{"ok": true}}
+	})
+
+	recorder := itamae.NewTraceRecorder()
+	chef := itamae.New(p, nil)
+	chef.SetTracer(recorder)
+	chef.SetStrictTemplates(false) // No keyring in tests: leave the secret unresolved
+	chef.SetVariables(map[string]interface{}{"FIGMA_TOKEN": "figd-1234"})
+	if _, err := chef.Serve(context.Background(), traceBento()); err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	spans := recorder.Spans()
+	byName := make(map[string]itamae.Span)
+	kinds := make(map[string]int)
+	for _, span := range spans {
+		byName[span.Name] = span
+		kinds[span.Kind]++
+	}
+
+	// Nodes: bento, loop, 2 loop children, upload
+	if kinds[itamae.SpanNode] != 5 || kinds[itamae.SpanIteration] != 2 || kinds[itamae.SpanAttempt] != 2 {
+		t.Errorf("Unexpected span kinds: %v", kinds)
+	}
+
+	root := byName["Trace"]
+	if root.ParentID != "" {
+		t.Errorf("Root span should have no parent, got %s", root.ParentID)
+	}
+	if byName["Renders"].ParentID != root.ID {
+		t.Errorf("Loop span should be a child of the bento span")
+	}
+	if byName["Renders [1]"].ParentID != byName["Renders"].ID {
+		t.Errorf("Iteration span should be a child of the loop span")
+	}
+	if byName["Upload attempt 1"].Err == nil || byName["Upload attempt 2"].Err != nil {
+		t.Errorf("Expected first attempt to fail and second to succeed")
+	}
+
+	for _, span := range spans {
+		if span.Name != "Render" {
+			continue
+		}
+		params := span.Attributes["params"].(map[string]interface{})
+		values := params["values"].(map[string]interface{})
+		if values["note"] != "{{SECRETS.API_KEY}}" {
+			t.Errorf("Secret should not be resolved in trace, got %v", values["note"])
+		}
+		if values["token"] != "[redacted]" {
+			t.Errorf("Token should be redacted in trace, got %v", values["token"])
+		}
+		headers := params["headers"].(map[string]interface{})
+		if headers["Authorization"] != "[redacted]" {
+			t.Errorf("Header should be redacted in trace, got %v", headers["Authorization"])
+		}
+		if values["product"] != "chair" && values["product"] != "table" {
+			t.Errorf("Expected resolved product param, got %v", values["product"])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.WriteChromeTrace(&buf); err != nil {
+		t.Fatalf("WriteChromeTrace failed: %v", err)
+	}
+	var trace struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Chrome trace is not valid JSON: %v", err)
+	}
+	if len(trace.TraceEvents) != len(spans) {
+		t.Errorf("Expected %d trace events, got %d", len(spans), len(trace.TraceEvents))
+	}
+}
+
+// TestItamae_TraceHidesVariables tests that credentials from variables and
+// the environment don't reach the trace through URLs, args or bodies.
+func TestItamae_TraceHidesVariables(t *testing.T) {
+	t.Setenv("RENDER_KEY", "rk-env-5678")
+	p := pantry.New()
+	calls := make(map[string][]map[string]interface{})
+	p.RegisterFactory("fetch", func() neta.Executable { return &recordNeta{nodeType: "fetch", calls: calls} })
+
+	recorder := itamae.NewTraceRecorder()
+	chef := itamae.New(p, nil)
+	chef.SetTracer(recorder)
+	chef.SetVariables(map[string]interface{}{"FIGMA_TOKEN": "figd-1234"})
+	_, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "fetch",
+		Type: "fetch",
+		Name: "Fetch",
+		Parameters: map[string]interface{}{
+			"url":   "https://api.figma.com/v1/files?token={{.FIGMA_TOKEN}}",
+			"args":  []interface{}{"--key", "{{.RENDER_KEY}}"},
+			"body":  "key=$RENDER_KEY",
+			"query": "${{ \"key=\" + RENDER_KEY }}",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.WriteChromeTrace(&buf); err != nil {
+		t.Fatalf("WriteChromeTrace failed: %v", err)
+	}
+	for _, secret := range []string{"figd-1234", "rk-env-5678"} {
+		if bytes.Contains(buf.Bytes(), []byte(secret)) {
+			t.Errorf("Trace contains %q: %s", secret, buf.String())
+		}
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("token={{.FIGMA_TOKEN}}")) {
+		t.Errorf("Trace should keep the unresolved URL template: %s", buf.String())
+	}
+	if got := calls["fetch"][0]["args"].([]interface{})[1]; got != "rk-env-5678" {
+		t.Errorf("Node got key %v, want the resolved key", got)
+	}
+}
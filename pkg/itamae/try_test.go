@@ -0,0 +1,110 @@
+package itamae_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// recordNeta records the resolved params of each call under its node type.
+type recordNeta struct {
+	nodeType string
+	calls    map[string][]map[string]interface{}
+}
+
+func (r *recordNeta) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	r.calls[r.nodeType] = append(r.calls[r.nodeType], params)
+	return map[string]interface{}{"ok": true}, nil
+}
+
+// serveTryGroup runs a group whose "render" node fails renderFailures times.
+func serveTryGroup(t *testing.T, renderFailures int, catch, finally []neta.Definition) (
+	*itamae.Result, map[string][]map[string]interface{}, error) {
+	t.Helper()
+
+	renderCalls := 0
+	calls := make(map[string][]map[string]interface{})
+	p := pantry.New()
+	p.RegisterFactory("flaky", func() neta.Executable {
+		return &flakyNeta{calls: &renderCalls, failures: renderFailures, output: map[string]interface{}{"ok": true}}
+	})
+	p.RegisterFactory("notify", func() neta.Executable { return &recordNeta{nodeType: "notify", calls: calls} })
+	p.RegisterFactory("cleanup", func() neta.Executable { return &recordNeta{nodeType: "cleanup", calls: calls} })
+
+	chef := itamae.New(p, nil)
+	result, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:      "renders",
+		Type:    "group",
+		Name:    "Renders",
+		Nodes:   []neta.Definition{{ID: "render", Type: "flaky", Name: "Render"}},
+		Catch:   catch,
+		Finally: finally,
+	})
+	return result, calls, err
+}
+
+var (
+	notifyNode = neta.Definition{
+		ID:   "notify",
+		Type: "notify",
+		Name: "Post Failure",
+		Parameters: map[string]interface{}{
+			"failed":  "{{.error.nodeId}}",
+			"message": "{{.error.message}}",
+		},
+	}
+	cleanupNode = neta.Definition{ID: "cleanup", Type: "cleanup", Name: "Delete Partial Renders"}
+)
+
+// TestItamae_CatchHandlesFailure tests that catch nodes see the failure and handle it.
+func TestItamae_CatchHandlesFailure(t *testing.T) {
+	_, calls, err := serveTryGroup(t, 1, []neta.Definition{notifyNode}, []neta.Definition{cleanupNode})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if len(calls["notify"]) != 1 {
+		t.Fatalf("notify calls = %d, want 1", len(calls["notify"]))
+	}
+	if got := calls["notify"][0]["failed"]; got != "render" {
+		t.Errorf("error.nodeId = %v, want render", got)
+	}
+	if got := calls["notify"][0]["message"]; got != "connection reset (call 1)" {
+		t.Errorf("error.message = %v, want the render error", got)
+	}
+	if len(calls["cleanup"]) != 1 {
+		t.Errorf("cleanup calls = %d, want 1", len(calls["cleanup"]))
+	}
+}
+
+// TestItamae_FinallyRunsOnFailure tests that finally runs and the error still propagates.
+func TestItamae_FinallyRunsOnFailure(t *testing.T) {
+	_, calls, err := serveTryGroup(t, 1, nil, []neta.Definition{cleanupNode})
+	if err == nil {
+		t.Fatal("Expected error without a catch branch")
+	}
+	if len(calls["cleanup"]) != 1 {
+		t.Errorf("cleanup calls = %d, want 1", len(calls["cleanup"]))
+	}
+}
+
+// TestItamae_CatchSkippedOnSuccess tests that catch nodes are skipped when nothing fails.
+func TestItamae_CatchSkippedOnSuccess(t *testing.T) {
+	result, calls, err := serveTryGroup(t, 0, []neta.Definition{notifyNode}, []neta.Definition{cleanupNode})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if len(calls["notify"]) != 0 {
+		t.Errorf("notify calls = %d, want 0", len(calls["notify"]))
+	}
+	if result.NodesSkipped != 1 {
+		t.Errorf("NodesSkipped = %d, want 1", result.NodesSkipped)
+	}
+	if len(calls["cleanup"]) != 1 {
+		t.Errorf("cleanup calls = %d, want 1", len(calls["cleanup"]))
+	}
+}
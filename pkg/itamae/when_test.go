@@ -0,0 +1,97 @@
+package itamae_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/pantry"
+)
+
+// newWhenChef creates a chef whose "record" neta records its params.
+func newWhenChef() (*itamae.Itamae, map[string][]map[string]interface{}) {
+	calls := make(map[string][]map[string]interface{})
+	p := pantry.New()
+	p.RegisterFactory("record", func() neta.Executable { return &recordNeta{nodeType: "record", calls: calls} })
+	return itamae.New(p, nil), calls
+}
+
+// TestItamae_WhenSkipsNode tests that a false when condition skips the node
+// while its downstream nodes still run, in both schedulers.
+func TestItamae_WhenSkipsNode(t *testing.T) {
+	for _, maxConcurrency := range []float64{1, 2} {
+		chef, calls := newWhenChef()
+		chef.SetVariables(map[string]interface{}{"overlay": ""})
+
+		result, err := chef.Serve(context.Background(), &neta.Definition{
+			ID:         "product",
+			Type:       "group",
+			Parameters: map[string]interface{}{"maxConcurrency": maxConcurrency},
+			Nodes: []neta.Definition{
+				{ID: "render", Type: "record", Parameters: map[string]interface{}{"step": "render"}},
+				{ID: "overlay", Type: "record", When: `overlay != ""`, Parameters: map[string]interface{}{"step": "overlay"}},
+				{ID: "upload", Type: "record", Parameters: map[string]interface{}{"step": "upload"}},
+			},
+			Edges: []neta.Edge{
+				{ID: "e1", Source: "render", Target: "overlay"},
+				{ID: "e2", Source: "overlay", Target: "upload"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("maxConcurrency %v: Serve failed: %v", maxConcurrency, err)
+		}
+
+		var steps []interface{}
+		for _, call := range calls["record"] {
+			steps = append(steps, call["step"])
+		}
+		if len(steps) != 2 || steps[0] != "render" || steps[1] != "upload" {
+			t.Errorf("maxConcurrency %v: steps = %v, want [render upload]", maxConcurrency, steps)
+		}
+		if result.NodesSkipped != 1 {
+			t.Errorf("maxConcurrency %v: NodesSkipped = %d, want 1", maxConcurrency, result.NodesSkipped)
+		}
+		if _, ok := result.NodeOutputs["overlay"]; ok {
+			t.Errorf("maxConcurrency %v: skipped node should have no output", maxConcurrency)
+		}
+	}
+}
+
+// TestItamae_WhenInLoop tests that a loop child's when condition is
+// evaluated for every item.
+func TestItamae_WhenInLoop(t *testing.T) {
+	chef, calls := newWhenChef()
+
+	_, err := chef.Serve(context.Background(), &neta.Definition{
+		ID:   "products",
+		Type: "loop",
+		Parameters: map[string]interface{}{
+			"mode": "forEach",
+			"items": []interface{}{
+				map[string]interface{}{"sku": "A", "overlay": "a.png"},
+				map[string]interface{}{"sku": "B"},
+			},
+		},
+		Nodes: []neta.Definition{
+			{ID: "overlay", Type: "record", When: "item.overlay != nil", Parameters: map[string]interface{}{"sku": "{{.item.sku}}"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Serve failed: %v", err)
+	}
+
+	if len(calls["record"]) != 1 || calls["record"][0]["sku"] != "A" {
+		t.Errorf("calls = %v, want only product A", calls["record"])
+	}
+}
+
+// TestItamae_WhenInvalid tests that a condition that can't be evaluated fails the node.
+func TestItamae_WhenInvalid(t *testing.T) {
+	chef, _ := newWhenChef()
+
+	_, err := chef.Serve(context.Background(), &neta.Definition{ID: "overlay", Type: "record", When: `"yes"`})
+	if err == nil {
+		t.Fatal("Expected error for a non-boolean when condition")
+	}
+}
@@ -0,0 +1,194 @@
+package logs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RunRecord is the history entry for one bento run.
+// Stored as {bento-home}/history/{id}.json.
+type RunRecord struct {
+	ID          string                 `json:"id"`
+	Bento       string                 `json:"bento"`               // Bento name
+	BentoPath   string                 `json:"bentoPath,omitempty"` // Absolute path of the bento file
+	Variables   map[string]interface{} `json:"variables,omitempty"`
+	StartedAt   time.Time              `json:"startedAt"`
+	FinishedAt  time.Time              `json:"finishedAt"`
+	Status      string                 `json:"status"` // "success", "failed" or "cancelled"
+	Error       string                 `json:"error,omitempty"`
+	ResumedFrom string                 `json:"resumedFrom,omitempty"` // Run whose checkpoint this run resumed
+	Nodes       []NodeRecord           `json:"nodes"`
+}
+
+// NodeRecord is the outcome of one node in a run, in start order.
+type NodeRecord struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Status     string `json:"status"` // "completed", "failed", "skipped" or "cached"
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Duration returns how long the run took.
+func (r *RunRecord) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Matches reports whether the run belongs to the given bento.
+// The bento may be given by name, file name or path.
+func (r *RunRecord) Matches(bento string) bool {
+	if bento == "" {
+		return true
+	}
+	if r.Bento == bento || r.BentoPath == bento {
+		return true
+	}
+	base := strings.TrimSuffix(filepath.Base(r.BentoPath), ".bento.json")
+	return base == strings.TrimSuffix(filepath.Base(bento), ".bento.json")
+}
+
+// NewRunID generates a sortable, unique run ID (e.g. "20250101-150405-a1b2c3").
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return time.Now().Format("20060102-150405")
+	}
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// GetHistoryDirectory returns the path to the run history directory.
+// If bentoHome is empty, defaults to ~/.bento
+func GetHistoryDirectory(bentoHome string) (string, error) {
+	logsDir, err := GetLogsDirectory(bentoHome)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(logsDir), "history"), nil
+}
+
+// SaveRun writes a run record to the history directory.
+func SaveRun(bentoHome string, record *RunRecord) error {
+	if record.ID == "" {
+		return fmt.Errorf("run record has no ID")
+	}
+
+	dir, err := GetHistoryDirectory(bentoHome)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run record: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, record.ID+".json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+	return nil
+}
+
+// LoadRun reads the run record with the given ID.
+func LoadRun(bentoHome, id string) (*RunRecord, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.Contains(id, "..") {
+		return nil, fmt.Errorf("invalid run ID '%s'", id)
+	}
+
+	dir, err := GetHistoryDirectory(bentoHome)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("run '%s' not found", id)
+		}
+		return nil, fmt.Errorf("failed to read run record: %w", err)
+	}
+
+	var record RunRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to parse run record '%s': %w", id, err)
+	}
+	return &record, nil
+}
+
+// ListRuns returns the recorded runs of a bento (all bentos if bento is empty),
+// newest first. Unreadable records are skipped.
+func ListRuns(bentoHome, bento string) ([]RunRecord, error) {
+	dir, err := GetHistoryDirectory(bentoHome)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []RunRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	runs := []RunRecord{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		record, err := LoadRun(bentoHome, strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil || !record.Matches(bento) {
+			continue
+		}
+		runs = append(runs, *record)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return runs, nil
+}
+
+// LastRuns returns the most recent run of each bento, keyed by bento path.
+func LastRuns(bentoHome string) (map[string]RunRecord, error) {
+	runs, err := ListRuns(bentoHome, "")
+	if err != nil {
+		return nil, err
+	}
+
+	last := make(map[string]RunRecord)
+	for _, run := range runs {
+		if _, seen := last[run.BentoPath]; !seen && run.BentoPath != "" {
+			last[run.BentoPath] = run // runs are sorted newest first
+		}
+	}
+	return last, nil
+}
+
+// FormatLastRun summarizes a run for bento lists (e.g. "failed 3h ago").
+func FormatLastRun(record RunRecord, now time.Time) string {
+	return fmt.Sprintf("%s %s", record.Status, formatAge(now.Sub(record.StartedAt)))
+}
+
+// formatAge formats how long ago something happened.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,96 @@
+package logs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistory_SaveAndList(t *testing.T) {
+	bentoHome := t.TempDir()
+	start := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+
+	runs := []RunRecord{
+		{ID: "run-1", Bento: "Renders", BentoPath: "/bentos/renders.bento.json", StartedAt: start, Status: "failed"},
+		{ID: "run-2", Bento: "Renders", BentoPath: "/bentos/renders.bento.json", StartedAt: start.Add(time.Hour), Status: "success"},
+		{ID: "run-3", Bento: "Uploads", BentoPath: "/bentos/uploads.bento.json", StartedAt: start.Add(2 * time.Hour), Status: "success"},
+	}
+	for i := range runs {
+		if err := SaveRun(bentoHome, &runs[i]); err != nil {
+			t.Fatalf("SaveRun failed: %v", err)
+		}
+	}
+
+	all, err := ListRuns(bentoHome, "")
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != "run-3" {
+		t.Errorf("Expected 3 runs newest first, got %+v", all)
+	}
+
+	renders, err := ListRuns(bentoHome, "renders")
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(renders) != 2 || renders[0].ID != "run-2" {
+		t.Errorf("Expected 2 runs of renders, got %+v", renders)
+	}
+
+	last, err := LastRuns(bentoHome)
+	if err != nil {
+		t.Fatalf("LastRuns failed: %v", err)
+	}
+	if last["/bentos/renders.bento.json"].ID != "run-2" {
+		t.Errorf("Expected run-2 as last run of renders, got %+v", last)
+	}
+}
+
+func TestHistory_LoadRunNotFound(t *testing.T) {
+	bentoHome := t.TempDir()
+
+	if _, err := LoadRun(bentoHome, "missing"); err == nil {
+		t.Error("Expected error for missing run")
+	}
+	if _, err := LoadRun(bentoHome, "../secrets"); err == nil {
+		t.Error("Expected error for run ID with path separators")
+	}
+
+	runs, err := ListRuns(bentoHome, "")
+	if err != nil || len(runs) != 0 {
+		t.Errorf("Expected no runs without history directory, got %v, %v", runs, err)
+	}
+}
+
+func TestRecorder_NodeOutcomes(t *testing.T) {
+	recorder := NewRecorder("Renders", "/bentos/renders.bento.json", map[string]interface{}{"ZOOM": 1.5})
+
+	recorder.SendNodeStarted("read", "Read CSV", "spreadsheet")
+	recorder.SendNodeCompleted("read", 20*time.Millisecond, nil)
+	recorder.SendNodeSkipped("overlay", "Render Overlay", "shell-command")
+	recorder.SendNodeStarted("render", "Render", "shell-command")
+	recorder.SendNodeCompleted("render", time.Second, errors.New("exit status 1"))
+
+	record := recorder.Finish("run-1", "failed", errors.New("render failed"))
+
+	if record.ID != "run-1" || record.Status != "failed" || record.Error != "render failed" {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.Variables["ZOOM"] != 1.5 {
+		t.Errorf("Expected variables to be recorded, got %v", record.Variables)
+	}
+
+	want := []NodeRecord{
+		{ID: "read", Name: "Read CSV", Type: "spreadsheet", Status: "completed", DurationMs: 20},
+		{ID: "overlay", Name: "Render Overlay", Type: "shell-command", Status: "skipped"},
+		{ID: "render", Name: "Render", Type: "shell-command", Status: "failed", DurationMs: 1000, Error: "exit status 1"},
+	}
+	if len(record.Nodes) != len(want) {
+		t.Fatalf("Expected %d nodes, got %+v", len(want), record.Nodes)
+	}
+	for i, node := range want {
+		if record.Nodes[i] != node {
+			t.Errorf("Node %d = %+v, want %+v", i, record.Nodes[i], node)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logs
+
+import (
+	"sync"
+	"time"
+)
+
+// Recorder collects per-node outcomes for a run record.
+// It implements itamae.ProgressMessenger, so it can be passed to the itamae
+// (alone or alongside a display messenger).
+type Recorder struct {
+	mu     sync.Mutex
+	record RunRecord
+	index  map[string]int // node ID -> position in record.Nodes
+}
+
+// NewRecorder starts recording a run of the given bento.
+func NewRecorder(bento, bentoPath string, variables map[string]interface{}) *Recorder {
+	return &Recorder{
+		record: RunRecord{
+			Bento:     bento,
+			BentoPath: bentoPath,
+			Variables: variables,
+			StartedAt: time.Now(),
+			Nodes:     []NodeRecord{},
+		},
+		index: make(map[string]int),
+	}
+}
+
+// Finish completes the record with the run's ID, status and error.
+func (r *Recorder) Finish(id, status string, err error) *RunRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := r.record
+	record.Nodes = append([]NodeRecord{}, r.record.Nodes...)
+	record.ID = id
+	record.Status = status
+	record.FinishedAt = time.Now()
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return &record
+}
+
+// node returns the record for a node, adding it on first use.
+// Callers must hold r.mu.
+func (r *Recorder) node(id string) *NodeRecord {
+	idx, ok := r.index[id]
+	if !ok {
+		idx = len(r.record.Nodes)
+		r.index[id] = idx
+		r.record.Nodes = append(r.record.Nodes, NodeRecord{ID: id})
+	}
+	return &r.record.Nodes[idx]
+}
+
+// SendNodeStarted records the node's name and type.
+func (r *Recorder) SendNodeStarted(path, name, nodeType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node := r.node(path)
+	node.Name = name
+	node.Type = nodeType
+	node.Status = "running"
+}
+
+// SendNodeCompleted records the node's duration and outcome.
+func (r *Recorder) SendNodeCompleted(path string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node := r.node(path)
+	node.DurationMs = duration.Milliseconds()
+	node.Status = "completed"
+	if err != nil {
+		node.Status = "failed"
+		node.Error = err.Error()
+	}
+}
+
+// SendLoopChild is a no-op (loops are recorded as one node).
+func (r *Recorder) SendLoopChild(loopPath, childName string, index, total int) {}
+
+// SendNodeSkipped records a skipped node.
+func (r *Recorder) SendNodeSkipped(path, name, nodeType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node := r.node(path)
+	node.Name = name
+	node.Type = nodeType
+	node.Status = "skipped"
+}
+
+// SendNodeRetry is a no-op (only the final outcome is recorded).
+func (r *Recorder) SendNodeRetry(path string, attempt, maxAttempts int, err error) {}
+
+// SendNodeCached records a node served from the output cache.
+func (r *Recorder) SendNodeCached(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.node(path).Status = "cached"
+}
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/logs"
 	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/omakase"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -43,6 +45,9 @@ func loadBentos() ([]list.Item, error) {
 		return nil, err
 	}
 
+	// Last-run status is optional - ignore history read errors
+	lastRuns, _ := logs.LastRuns(bentoHome)
+
 	var bentoItems []BentoItem
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".bento.json") {
@@ -54,10 +59,14 @@ func loadBentos() ([]list.Item, error) {
 		// Strip number prefixes for display
 		displayName := stripNumberPrefix(name)
 
-		bentoItems = append(bentoItems, BentoItem{
+		item := BentoItem{
 			Name:     displayName,
 			FilePath: filepath.Join(bentosDir, entry.Name()),
-		})
+		}
+		if run, ok := lastRuns[absPath(item.FilePath)]; ok {
+			item.LastRun = logs.FormatLastRun(run, time.Now())
+		}
+		bentoItems = append(bentoItems, item)
 	}
 
 	// Load saved order and apply it
@@ -93,6 +102,30 @@ func loadBentoDefinition(path string) (*neta.Definition, error) {
 	return &def, nil
 }
 
+// bentoDirLoader loads sub-bentos by name from the bentos directory.
+type bentoDirLoader struct {
+	dir string
+}
+
+// LoadBento loads <dir>/<name>.bento.json.
+func (l bentoDirLoader) LoadBento(ctx context.Context, name string) (*neta.Definition, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return nil, fmt.Errorf("invalid bento name '%s'", name)
+	}
+	return loadBentoDefinition(filepath.Join(l.dir, name+".bento.json"))
+}
+
+// resolveFormVariables type-checks the form values for the bento's declared variables.
+func resolveFormVariables(def *neta.Definition, holders map[string]*string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(holders))
+	for name, holder := range holders {
+		if holder != nil && *holder != "" {
+			values[name] = *holder
+		}
+	}
+	return omakase.ResolveVariables(def.Variables, values)
+}
+
 // runBento executes the selected bento
 func (m Model) runBento() (tea.Model, tea.Cmd) {
 	// Read bento file
@@ -154,12 +187,23 @@ func (m Model) executeBentoAsync(logChan chan string) (tea.Cmd, tea.Cmd) {
 		}
 		defer logFile.Close()
 
-		// Create chef with logger (no messenger needed)
-		chef := itamae.NewWithMessenger(p, logger, nil)
+		vars, err := resolveFormVariables(def, m.varHolders)
+		if err != nil {
+			return executionCompleteMsg{err: err}
+		}
+
+		// Create chef with logger (the only messenger records run history)
+		recorder := logs.NewRecorder(def.Name, absPath(m.selectedBento), vars)
+		chef := itamae.NewWithMessenger(p, logger, recorder)
+		chef.SetBentoLoader(bentoDirLoader{dir: filepath.Join(LoadBentoHome(), "bentos")})
+		chef.SetBentoValidator(omakase.New())
+		chef.SetBentoDir(filepath.Dir(m.selectedBento))
+		chef.SetVariables(vars)
 
 		start := time.Now()
-		_, err = chef.Serve(ctx, def)
+		result, err := chef.Serve(ctx, def)
 		duration := time.Since(start)
+		saveRunHistory(recorder, result, err)
 
 		return executionCompleteMsg{
 			err:      err,
@@ -169,3 +213,18 @@ func (m Model) executeBentoAsync(logChan chan string) (tea.Cmd, tea.Cmd) {
 
 	return execCmd, startCmd
 }
+
+// saveRunHistory records a TUI run in the run history.
+// History is best effort and never changes the run's outcome.
+func saveRunHistory(recorder *logs.Recorder, result *itamae.Result, runErr error) {
+	record := recorder.Finish(logs.NewRunID(), string(result.Status), runErr)
+	_ = logs.SaveRun(LoadBentoHome(), record)
+}
+
+// absPath returns the absolute form of path (used to match run history).
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
@@ -0,0 +1,206 @@
+package miso
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// Event types written by EventMessenger.
+const (
+	EventRunStarted    = "run_started"
+	EventRunFinished   = "run_finished"
+	EventNodeStarted   = "node_started"
+	EventNodeCompleted = "node_completed"
+	EventNodeFailed    = "node_failed"
+	EventNodeSkipped   = "node_skipped"
+	EventNodeRetry     = "node_retry"
+	EventNodeCached    = "node_cached"
+	EventLoopIteration = "loop_iteration"
+	EventNodeOutput    = "node_output"
+)
+
+// Event is one line of the NDJSON event stream.
+// Fields that don't apply to an event type are omitted.
+type Event struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	NodeID     string    `json:"nodeId,omitempty"`
+	Path       string    `json:"path,omitempty"` // Node IDs from the root, joined with "/"
+	Name       string    `json:"name,omitempty"`
+	Type       string    `json:"type,omitempty"`
+	DurationMs *int64    `json:"durationMs,omitempty"`
+	Error      string    `json:"error,omitempty"`
+
+	// Loop iterations
+	Index *int   `json:"index,omitempty"`
+	Total int    `json:"total,omitempty"`
+	Child string `json:"child,omitempty"`
+
+	// Retries
+	Attempt     int `json:"attempt,omitempty"`
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Streamed output
+	Line string `json:"line,omitempty"`
+
+	// Run finished
+	Status        string `json:"status,omitempty"`
+	NodesExecuted *int   `json:"nodesExecuted,omitempty"`
+	NodesSkipped  *int   `json:"nodesSkipped,omitempty"`
+	NodesCached   *int   `json:"nodesCached,omitempty"`
+}
+
+// EventMessenger writes execution progress as newline-delimited JSON.
+// Used by `bento run --events ndjson` so CI tooling doesn't have to parse
+// the human-readable output.
+type EventMessenger struct {
+	enc   *json.Encoder
+	nodes map[string]eventNode // node ID -> static node info
+	mu    sync.Mutex           // guards enc (nodes may run concurrently)
+}
+
+// eventNode is the static information about a node in the bento.
+type eventNode struct {
+	path     string
+	name     string
+	nodeType string
+}
+
+// NewEventMessenger creates a messenger that writes events for def to w.
+func NewEventMessenger(w io.Writer, def *neta.Definition) *EventMessenger {
+	m := &EventMessenger{
+		enc:   json.NewEncoder(w),
+		nodes: make(map[string]eventNode),
+	}
+	m.indexNodes(def, nil)
+	return m
+}
+
+// indexNodes records the path, name and type of every node in the tree.
+func (m *EventMessenger) indexNodes(def *neta.Definition, parent []string) {
+	path := append(append([]string{}, parent...), def.ID)
+	m.nodes[def.ID] = eventNode{
+		path:     strings.Join(path, "/"),
+		name:     def.Name,
+		nodeType: def.Type,
+	}
+
+	children := append(append(append(append([]neta.Definition{}, def.Nodes...), def.Catch...), def.OnCancel...), def.Finally...)
+	for idx := range children {
+		m.indexNodes(&children[idx], path)
+	}
+}
+
+// nodeEvent creates an event for a node, filled in with its static info.
+func (m *EventMessenger) nodeEvent(event, nodeID string) Event {
+	info, ok := m.nodes[nodeID]
+	if !ok {
+		info = eventNode{path: nodeID}
+	}
+	return Event{
+		Event:  event,
+		NodeID: nodeID,
+		Path:   info.path,
+		Name:   info.name,
+		Type:   info.nodeType,
+	}
+}
+
+// write encodes one event as a single line.
+func (m *EventMessenger) write(e Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e.Time = time.Now().UTC()
+	_ = m.enc.Encode(e) // Best effort - events must never fail the run
+}
+
+// SendRunStarted writes the run_started event.
+func (m *EventMessenger) SendRunStarted(def *neta.Definition) {
+	m.write(m.nodeEvent(EventRunStarted, def.ID))
+}
+
+// SendRunFinished writes the run_finished event with the result counts.
+func (m *EventMessenger) SendRunFinished(def *neta.Definition, result *itamae.Result) {
+	e := m.nodeEvent(EventRunFinished, def.ID)
+	e.Status = string(result.Status)
+	e.DurationMs = durationMs(result.Duration)
+	e.NodesExecuted = &result.NodesExecuted
+	e.NodesSkipped = &result.NodesSkipped
+	e.NodesCached = &result.NodesCached
+	if result.Error != nil {
+		e.Error = result.Error.Error()
+	}
+	m.write(e)
+}
+
+// SendNodeStarted writes the node_started event.
+func (m *EventMessenger) SendNodeStarted(path, name, nodeType string) {
+	e := m.nodeEvent(EventNodeStarted, path)
+	e.Name = name
+	e.Type = nodeType
+	m.write(e)
+}
+
+// SendNodeCompleted writes node_completed, or node_failed when err is set.
+func (m *EventMessenger) SendNodeCompleted(path string, duration time.Duration, err error) {
+	e := m.nodeEvent(EventNodeCompleted, path)
+	e.DurationMs = durationMs(duration)
+	if err != nil {
+		e.Event = EventNodeFailed
+		e.Error = err.Error()
+	}
+	m.write(e)
+}
+
+// SendLoopChild writes the loop_iteration event.
+func (m *EventMessenger) SendLoopChild(loopPath, childName string, index, total int) {
+	e := m.nodeEvent(EventLoopIteration, loopPath)
+	e.Index = &index
+	e.Total = total
+	e.Child = childName
+	m.write(e)
+}
+
+// SendNodeSkipped writes the node_skipped event.
+func (m *EventMessenger) SendNodeSkipped(path, name, nodeType string) {
+	e := m.nodeEvent(EventNodeSkipped, path)
+	e.Name = name
+	e.Type = nodeType
+	m.write(e)
+}
+
+// SendNodeRetry writes the node_retry event.
+func (m *EventMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	e := m.nodeEvent(EventNodeRetry, path)
+	e.Attempt = attempt
+	e.MaxAttempts = maxAttempts
+	if err != nil {
+		e.Error = err.Error()
+	}
+	m.write(e)
+}
+
+// SendNodeCached writes the node_cached event.
+func (m *EventMessenger) SendNodeCached(path string) {
+	m.write(m.nodeEvent(EventNodeCached, path))
+}
+
+// SendNodeOutput writes a node_output event for each streamed line.
+func (m *EventMessenger) SendNodeOutput(path, line string) {
+	e := m.nodeEvent(EventNodeOutput, path)
+	e.Line = line
+	m.write(e)
+}
+
+// durationMs converts a duration to milliseconds for the event stream.
+func durationMs(d time.Duration) *int64 {
+	ms := d.Milliseconds()
+	return &ms
+}
@@ -0,0 +1,70 @@
+package miso
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Develonaut/bento/pkg/itamae"
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// TestEventMessenger_NDJSON tests that each update is written as one JSON line.
+func TestEventMessenger_NDJSON(t *testing.T) {
+	def := &neta.Definition{
+		ID:   "root",
+		Type: "group",
+		Name: "Render",
+		Nodes: []neta.Definition{
+			{ID: "render", Type: "shell-command", Name: "Render Product"},
+		},
+	}
+
+	var buf bytes.Buffer
+	m := NewEventMessenger(&buf, def)
+	m.SendRunStarted(def)
+	m.SendNodeStarted("render", "Render Product", "shell-command")
+	m.SendNodeOutput("render", "Fra:1 Mem:12M")
+	m.SendNodeCompleted("render", 1500*time.Millisecond, errors.New("exit status 1"))
+	m.SendRunFinished(def, &itamae.Result{Status: itamae.StatusFailed, Error: errors.New("render failed")})
+
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Line is not valid JSON: %v (%s)", err, scanner.Text())
+		}
+		events = append(events, e)
+	}
+
+	wantTypes := []string{EventRunStarted, EventNodeStarted, EventNodeOutput, EventNodeFailed, EventRunFinished}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("Expected %d events, got %d", len(wantTypes), len(events))
+	}
+	for idx, want := range wantTypes {
+		if events[idx].Event != want {
+			t.Errorf("Event %d = %s, want %s", idx, events[idx].Event, want)
+		}
+		if events[idx].Time.IsZero() {
+			t.Errorf("Event %d has no timestamp", idx)
+		}
+	}
+
+	failed := events[3]
+	if failed.Path != "root/render" || failed.Type != "shell-command" || failed.Name != "Render Product" {
+		t.Errorf("Unexpected node info: %+v", failed)
+	}
+	if failed.Error != "exit status 1" || failed.DurationMs == nil || *failed.DurationMs != 1500 {
+		t.Errorf("Unexpected failure details: %+v", failed)
+	}
+	if events[2].Line != "Fra:1 Mem:12M" {
+		t.Errorf("Expected streamed line, got %q", events[2].Line)
+	}
+	if events[4].Status != "failed" || events[4].Error != "render failed" {
+		t.Errorf("Unexpected run_finished: %+v", events[4])
+	}
+}
@@ -18,6 +18,7 @@ const (
 	NodeRunning
 	NodeCompleted
 	NodeFailed
+	NodeSkipped
 )
 
 // NodeState tracks individual node execution.
@@ -32,6 +33,9 @@ type NodeState struct {
 	currentChild string // For loops: name of currently executing child
 	childIndex   int    // For loops: current iteration index
 	childTotal   int    // For loops: total iterations
+	attempt      int    // Current attempt when retrying (0 before any retry)
+	maxAttempts  int    // Attempts allowed by the retry policy
+	cached       bool   // Output was served from the cache
 }
 
 // Executor displays bento execution progress using Bubbletea.
@@ -67,6 +71,26 @@ type NodeCompletedMsg struct {
 	Error    error
 }
 
+// NodeCachedMsg signals that a node was served from the output cache.
+type NodeCachedMsg struct {
+	Path string
+}
+
+// NodeRetryMsg signals that a failed node is being retried.
+type NodeRetryMsg struct {
+	Path        string
+	Attempt     int
+	MaxAttempts int
+	Error       error
+}
+
+// NodeSkippedMsg signals that a node was skipped (inactive branch).
+type NodeSkippedMsg struct {
+	Path     string
+	Name     string
+	NodeType string
+}
+
 // ExecutionInitMsg initializes the executor with bento definition.
 type ExecutionInitMsg struct {
 	Definition *neta.Definition
@@ -136,6 +160,12 @@ func (e Executor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return e.handleNodeStartedMsg(msg)
 	case NodeCompletedMsg:
 		return e.handleNodeCompletedMsg(msg)
+	case NodeCachedMsg:
+		return e.handleNodeCachedMsg(msg)
+	case NodeRetryMsg:
+		return e.handleNodeRetryMsg(msg)
+	case NodeSkippedMsg:
+		return e.handleNodeSkippedMsg(msg)
 	case LoopChildMsg:
 		return e.handleLoopChildMsg(msg)
 	case ExecutionCompleteMsg:
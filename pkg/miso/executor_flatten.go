@@ -45,10 +45,12 @@ func flattenSingleNode(def neta.Definition, basePath string) []NodeState {
 }
 
 // flattenGroupNodes flattens all nodes in a group recursively.
+// Catch, onCancel and finally nodes follow the group's main nodes.
 func flattenGroupNodes(def neta.Definition, basePath string) []NodeState {
 	states := []NodeState{}
+	children := append(append(append(append([]neta.Definition{}, def.Nodes...), def.Catch...), def.OnCancel...), def.Finally...)
 
-	for idx, child := range def.Nodes {
+	for idx, child := range children {
 		// Use node ID if present (graph-based execution), otherwise use hierarchical path
 		path := getNodePath(child, basePath, idx)
 
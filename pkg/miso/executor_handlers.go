@@ -57,6 +57,29 @@ func (e Executor) handleNodeCompletedMsg(msg NodeCompletedMsg) (tea.Model, tea.C
 	return e, nil
 }
 
+// handleNodeCachedMsg handles cache hits.
+func (e Executor) handleNodeCachedMsg(msg NodeCachedMsg) (tea.Model, tea.Cmd) {
+	e.handleNodeCached(msg)
+	e.updateSequence()
+	e.updateProgress()
+	return e, nil
+}
+
+// handleNodeRetryMsg handles node retry attempts.
+func (e Executor) handleNodeRetryMsg(msg NodeRetryMsg) (tea.Model, tea.Cmd) {
+	e.handleNodeRetry(msg)
+	e.updateSequence()
+	return e, nil
+}
+
+// handleNodeSkippedMsg handles nodes skipped by branch routing.
+func (e Executor) handleNodeSkippedMsg(msg NodeSkippedMsg) (tea.Model, tea.Cmd) {
+	e.handleNodeSkipped(msg)
+	e.updateSequence()
+	e.updateProgress()
+	return e, nil
+}
+
 // handleLoopChildMsg handles loop child execution updates.
 func (e Executor) handleLoopChildMsg(msg LoopChildMsg) (tea.Model, tea.Cmd) {
 	e.handleLoopChild(msg)
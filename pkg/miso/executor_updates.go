@@ -30,6 +30,38 @@ func (e *Executor) handleNodeCompleted(msg NodeCompletedMsg) {
 	}
 }
 
+// handleNodeCached marks a node as completed from the cache.
+func (e *Executor) handleNodeCached(msg NodeCachedMsg) {
+	for i := range e.nodeStates {
+		if e.nodeStates[i].path == msg.Path {
+			e.nodeStates[i].status = NodeCompleted
+			e.nodeStates[i].cached = true
+			return
+		}
+	}
+}
+
+// handleNodeRetry records the current attempt of a retrying node.
+func (e *Executor) handleNodeRetry(msg NodeRetryMsg) {
+	for i := range e.nodeStates {
+		if e.nodeStates[i].path == msg.Path {
+			e.nodeStates[i].attempt = msg.Attempt
+			e.nodeStates[i].maxAttempts = msg.MaxAttempts
+			return
+		}
+	}
+}
+
+// handleNodeSkipped updates node to skipped state.
+func (e *Executor) handleNodeSkipped(msg NodeSkippedMsg) {
+	for i := range e.nodeStates {
+		if e.nodeStates[i].path == msg.Path {
+			e.nodeStates[i].status = NodeSkipped
+			return
+		}
+	}
+}
+
 // handleLoopChild updates loop with current child execution info.
 func (e *Executor) handleLoopChild(msg LoopChildMsg) {
 	for i := range e.nodeStates {
@@ -55,6 +87,9 @@ func (e *Executor) updateSequence() {
 			CurrentChild: node.currentChild,
 			ChildIndex:   node.childIndex,
 			ChildTotal:   node.childTotal,
+			Attempt:      node.attempt,
+			MaxAttempts:  node.maxAttempts,
+			Cached:       node.cached,
 		}
 	}
 	e.sequence.SetSteps(steps)
@@ -71,6 +106,8 @@ func convertNodeStatusToStepStatus(status NodeStatus) StepStatus {
 		return StepCompleted
 	case NodeFailed:
 		return StepFailed
+	case NodeSkipped:
+		return StepSkipped
 	default:
 		return StepPending
 	}
@@ -85,7 +122,7 @@ func (e *Executor) updateProgress() {
 
 	completed := 0
 	for _, node := range e.nodeStates {
-		if node.status == NodeCompleted || node.status == NodeFailed {
+		if node.status == NodeCompleted || node.status == NodeFailed || node.status == NodeSkipped {
 			completed++
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Develonaut/bento/pkg/neta"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -75,7 +76,7 @@ func (m Model) showForm() (tea.Model, tea.Cmd) {
 	// Separate path variables from other variables
 	var pathVars, otherVars []Variable
 	for _, v := range sortedVars {
-		if isPathVariable(v.Name) {
+		if v.Type == neta.VariablePath || isPathVariable(v.Name) {
 			pathVars = append(pathVars, v)
 		} else {
 			otherVars = append(otherVars, v)
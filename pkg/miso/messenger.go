@@ -2,8 +2,10 @@ package miso
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/Develonaut/bento/pkg/itamae"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -28,6 +30,23 @@ type ProgressMessenger interface {
 	// index: current iteration index (0-based)
 	// total: total number of iterations
 	SendLoopChild(loopPath, childName string, index, total int)
+
+	// SendNodeSkipped notifies that a node was skipped (inactive branch).
+	// path: node path in tree
+	// name: human-readable node name
+	// nodeType: node type
+	SendNodeSkipped(path, name, nodeType string)
+
+	// SendNodeRetry notifies that a failed node is about to be retried.
+	// path: node path in tree
+	// attempt: the upcoming attempt number (2 for the first retry)
+	// maxAttempts: total attempts allowed by the retry policy
+	// err: the error that triggered the retry
+	SendNodeRetry(path string, attempt, maxAttempts int, err error)
+
+	// SendNodeCached notifies that a started node was served from the output cache.
+	// path: node path in tree
+	SendNodeCached(path string)
 }
 
 // BubbletMessenger sends progress messages to a Bubbletea program.
@@ -77,12 +96,43 @@ func (m *BubbletMessenger) SendLoopChild(loopPath, childName string, index, tota
 	}
 }
 
+// SendNodeSkipped sends node skipped message to Bubbletea.
+func (m *BubbletMessenger) SendNodeSkipped(path, name, nodeType string) {
+	if m.program != nil {
+		m.program.Send(NodeSkippedMsg{
+			Path:     path,
+			Name:     name,
+			NodeType: nodeType,
+		})
+	}
+}
+
+// SendNodeRetry sends node retry message to Bubbletea.
+func (m *BubbletMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	if m.program != nil {
+		m.program.Send(NodeRetryMsg{
+			Path:        path,
+			Attempt:     attempt,
+			MaxAttempts: maxAttempts,
+			Error:       err,
+		})
+	}
+}
+
+// SendNodeCached sends node cache hit message to Bubbletea.
+func (m *BubbletMessenger) SendNodeCached(path string) {
+	if m.program != nil {
+		m.program.Send(NodeCachedMsg{Path: path})
+	}
+}
+
 // SimpleMessenger prints simple progress updates for non-TTY mode.
 // Used for CI/CD, pipes, and redirects where Bubbletea cannot run.
 type SimpleMessenger struct {
 	theme    *Theme
 	palette  Palette
 	nodeInfo map[string]nodeStartInfo // stores node info from start to completion
+	mu       sync.Mutex               // guards nodeInfo (nodes may run concurrently)
 }
 
 // nodeStartInfo stores information about a started node.
@@ -102,6 +152,9 @@ func NewSimpleMessenger(theme *Theme, palette Palette) *SimpleMessenger {
 
 // SendNodeStarted stores node start information (doesn't print yet).
 func (m *SimpleMessenger) SendNodeStarted(path, name, nodeType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// Store node info for when it completes
 	m.nodeInfo[path] = nodeStartInfo{
 		name:     name,
@@ -112,6 +165,7 @@ func (m *SimpleMessenger) SendNodeStarted(path, name, nodeType string) {
 // SendNodeCompleted prints the complete node execution line.
 func (m *SimpleMessenger) SendNodeCompleted(path string, duration time.Duration, err error) {
 	// Get stored node info
+	m.mu.Lock()
 	info, ok := m.nodeInfo[path]
 	if !ok {
 		// Fallback if we don't have the info
@@ -122,6 +176,7 @@ func (m *SimpleMessenger) SendNodeCompleted(path string, duration time.Duration,
 
 	// Clean up stored info
 	delete(m.nodeInfo, path)
+	m.mu.Unlock()
 
 	if err != nil {
 		// Print: Failed NETA:file-system Create Product Directory… (error message)
@@ -148,6 +203,60 @@ func (m *SimpleMessenger) SendLoopChild(loopPath, childName string, index, total
 	// No-op: Simple mode doesn't show real-time child execution
 }
 
+// SendNodeSkipped prints the skipped node line.
+func (m *SimpleMessenger) SendNodeSkipped(path, name, nodeType string) {
+	// Print: Skipped NETA:shell-command Render Overlay…
+	fmt.Printf("  %s NETA:%s %s…\n",
+		m.theme.Subtle.Render(StatusWordSkipped),
+		nodeType,
+		name)
+}
+
+// SendNodeRetry prints the retry line.
+func (m *SimpleMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	m.mu.Lock()
+	info := m.nodeInfo[path]
+	m.mu.Unlock()
+
+	fmt.Print(formatRetryLine(m.theme, info, path, attempt, maxAttempts, err))
+}
+
+// SendNodeCached prints the cache hit line.
+func (m *SimpleMessenger) SendNodeCached(path string) {
+	m.mu.Lock()
+	info := m.nodeInfo[path]
+	delete(m.nodeInfo, path)
+	m.mu.Unlock()
+
+	fmt.Print(formatCachedLine(m.theme, info, path))
+}
+
+// formatCachedLine formats a cache hit for simple and callback output.
+// Example: Perfected NETA:shell-command Render Product… (cached)
+func formatCachedLine(theme *Theme, info nodeStartInfo, path string) string {
+	if info.name == "" {
+		info.name = path
+	}
+	return fmt.Sprintf("  %s NETA:%s %s… %s\n",
+		theme.Success.Render(getStatusLabel(StepCompleted, info.name)),
+		info.nodeType,
+		info.name,
+		theme.Subtle.Render("(cached)"))
+}
+
+// formatRetryLine formats a retry notice for simple and callback output.
+// Example: Retrying NETA:http-request Fetch Overlay… attempt 2/3 (connection reset)
+func formatRetryLine(theme *Theme, info nodeStartInfo, path string, attempt, maxAttempts int, err error) string {
+	if info.name == "" {
+		info.name = path
+	}
+	return fmt.Sprintf("  %s NETA:%s %s… %s\n",
+		theme.Warning.Render("Retrying"),
+		info.nodeType,
+		info.name,
+		theme.Subtle.Render(fmt.Sprintf("attempt %d/%d (%v)", attempt, maxAttempts, err)))
+}
+
 // formatSimpleDuration formats duration for simple display.
 func formatSimpleDuration(d time.Duration) string {
 	if d < time.Second {
@@ -167,6 +276,7 @@ type CallbackMessenger struct {
 	theme    *Theme
 	palette  Palette
 	nodeInfo map[string]nodeStartInfo
+	mu       sync.Mutex   // guards nodeInfo (nodes may run concurrently)
 	onLog    func(string) // Callback to send log messages
 }
 
@@ -182,6 +292,9 @@ func NewCallbackMessenger(theme *Theme, palette Palette, onLog func(string)) *Ca
 
 // SendNodeStarted stores node start information.
 func (m *CallbackMessenger) SendNodeStarted(path, name, nodeType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.nodeInfo[path] = nodeStartInfo{
 		name:     name,
 		nodeType: nodeType,
@@ -190,11 +303,13 @@ func (m *CallbackMessenger) SendNodeStarted(path, name, nodeType string) {
 
 // SendNodeCompleted formats and sends log via callback.
 func (m *CallbackMessenger) SendNodeCompleted(path string, duration time.Duration, err error) {
+	m.mu.Lock()
 	info, ok := m.nodeInfo[path]
 	if !ok {
 		info = nodeStartInfo{name: path}
 	}
 	delete(m.nodeInfo, path)
+	m.mu.Unlock()
 
 	var logLine string
 	if err != nil {
@@ -223,3 +338,101 @@ func (m *CallbackMessenger) SendNodeCompleted(path string, duration time.Duratio
 func (m *CallbackMessenger) SendLoopChild(loopPath, childName string, index, total int) {
 	// No-op for now
 }
+
+// SendNodeSkipped formats and sends the skipped node log via callback.
+func (m *CallbackMessenger) SendNodeSkipped(path, name, nodeType string) {
+	if m.onLog == nil {
+		return
+	}
+	m.onLog(fmt.Sprintf("  %s NETA:%s %s…\n",
+		m.theme.Subtle.Render(StatusWordSkipped),
+		nodeType,
+		name))
+}
+
+// SendNodeRetry sends the retry notice via callback.
+func (m *CallbackMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	if m.onLog == nil {
+		return
+	}
+
+	m.mu.Lock()
+	info := m.nodeInfo[path]
+	m.mu.Unlock()
+
+	m.onLog(formatRetryLine(m.theme, info, path, attempt, maxAttempts, err))
+}
+
+// SendNodeCached sends the cache hit line via callback.
+func (m *CallbackMessenger) SendNodeCached(path string) {
+	m.mu.Lock()
+	info := m.nodeInfo[path]
+	delete(m.nodeInfo, path)
+	m.mu.Unlock()
+
+	if m.onLog != nil {
+		m.onLog(formatCachedLine(m.theme, info, path))
+	}
+}
+
+// MultiMessenger sends every progress update to several messengers.
+// Used to combine display output with the event stream and run history.
+type MultiMessenger struct {
+	messengers []itamae.ProgressMessenger
+}
+
+// NewMultiMessenger creates a messenger that forwards to all given messengers.
+func NewMultiMessenger(messengers ...itamae.ProgressMessenger) *MultiMessenger {
+	return &MultiMessenger{messengers: messengers}
+}
+
+// SendNodeStarted forwards to all messengers.
+func (m *MultiMessenger) SendNodeStarted(path, name, nodeType string) {
+	for _, messenger := range m.messengers {
+		messenger.SendNodeStarted(path, name, nodeType)
+	}
+}
+
+// SendNodeCompleted forwards to all messengers.
+func (m *MultiMessenger) SendNodeCompleted(path string, duration time.Duration, err error) {
+	for _, messenger := range m.messengers {
+		messenger.SendNodeCompleted(path, duration, err)
+	}
+}
+
+// SendLoopChild forwards to all messengers.
+func (m *MultiMessenger) SendLoopChild(loopPath, childName string, index, total int) {
+	for _, messenger := range m.messengers {
+		messenger.SendLoopChild(loopPath, childName, index, total)
+	}
+}
+
+// SendNodeSkipped forwards to all messengers.
+func (m *MultiMessenger) SendNodeSkipped(path, name, nodeType string) {
+	for _, messenger := range m.messengers {
+		messenger.SendNodeSkipped(path, name, nodeType)
+	}
+}
+
+// SendNodeRetry forwards to all messengers.
+func (m *MultiMessenger) SendNodeRetry(path string, attempt, maxAttempts int, err error) {
+	for _, messenger := range m.messengers {
+		messenger.SendNodeRetry(path, attempt, maxAttempts, err)
+	}
+}
+
+// SendNodeCached forwards to all messengers.
+func (m *MultiMessenger) SendNodeCached(path string) {
+	for _, messenger := range m.messengers {
+		messenger.SendNodeCached(path)
+	}
+}
+
+// SendNodeOutput forwards streamed lines to the messengers that accept them.
+func (m *MultiMessenger) SendNodeOutput(path, line string) {
+	for _, messenger := range m.messengers {
+		if out, ok := messenger.(itamae.OutputMessenger); ok {
+			out.SendNodeOutput(path, line)
+		}
+	}
+}
@@ -84,10 +84,16 @@ type Model struct {
 type BentoItem struct {
 	Name     string
 	FilePath string
+	LastRun  string // Last-run status from the run history (e.g. "failed 3h ago")
 }
 
-func (i BentoItem) Title() string       { return i.Name }
-func (i BentoItem) Description() string { return CompressPath(i.FilePath) }
+func (i BentoItem) Title() string { return i.Name }
+func (i BentoItem) Description() string {
+	if i.LastRun == "" {
+		return CompressPath(i.FilePath)
+	}
+	return CompressPath(i.FilePath) + " · last run " + i.LastRun
+}
 func (i BentoItem) FilterValue() string { return i.Name }
 
 // SettingsItem represents a settings option
@@ -2,6 +2,7 @@ package miso
 
 import (
 	"github.com/Develonaut/bento/pkg/neta"
+	branch "github.com/Develonaut/bento/pkg/neta/library/branch"
 	editfields "github.com/Develonaut/bento/pkg/neta/library/editfields"
 	filesystem "github.com/Develonaut/bento/pkg/neta/library/filesystem"
 	group "github.com/Develonaut/bento/pkg/neta/library/group"
@@ -24,11 +25,13 @@ func createTUIPantry() *pantry.Pantry {
 	p.RegisterFactory("file-system", func() neta.Executable { return filesystem.New() })
 	p.RegisterFactory("group", func() neta.Executable { return group.New() })
 	p.RegisterFactory("http-request", func() neta.Executable { return httpneta.New() })
+	p.RegisterFactory("if", func() neta.Executable { return branch.NewIf() })
 	p.RegisterFactory("image", func() neta.Executable { return image.New() })
 	p.RegisterFactory("loop", func() neta.Executable { return loop.New() })
 	p.RegisterFactory("parallel", func() neta.Executable { return parallel.New() })
 	p.RegisterFactory("shell-command", func() neta.Executable { return shellcommand.New() })
 	p.RegisterFactory("spreadsheet", func() neta.Executable { return spreadsheet.New() })
+	p.RegisterFactory("switch", func() neta.Executable { return branch.NewSwitch() })
 	p.RegisterFactory("transform", func() neta.Executable { return transform.New() })
 
 	return p
@@ -20,6 +20,7 @@ const (
 	StepRunning
 	StepCompleted
 	StepFailed
+	StepSkipped
 )
 
 // Step represents a single step in a sequence.
@@ -32,6 +33,9 @@ type Step struct {
 	CurrentChild string // For loops: name of currently executing child
 	ChildIndex   int    // For loops: current iteration index
 	ChildTotal   int    // For loops: total iterations
+	Attempt      int    // Current attempt when retrying (0 before any retry)
+	MaxAttempts  int    // Attempts allowed by the retry policy
+	Cached       bool   // Output was served from the cache
 }
 
 // Sequence displays a list of execution steps with status indicators.
@@ -172,9 +176,15 @@ func (s *Sequence) buildStepStatus(step Step) string {
 
 // buildStepSuffix creates the duration suffix if applicable.
 func buildStepSuffix(step Step) string {
+	if step.Status == StepCompleted && step.Cached {
+		return "(cached)"
+	}
 	if (step.Status == StepCompleted || step.Status == StepFailed) && step.Duration > 0 {
 		return fmt.Sprintf("(%s)", step.Duration.Round(time.Millisecond).String())
 	}
+	if step.Status == StepRunning && step.Attempt > 1 {
+		return fmt.Sprintf("(attempt %d/%d)", step.Attempt, step.MaxAttempts)
+	}
 	return ""
 }
 
@@ -204,6 +214,8 @@ func getStatusLabel(status StepStatus, stepName string) string {
 		return StatusWordsCompleted[rand.Intn(len(StatusWordsCompleted))]
 	case StepFailed:
 		return StatusWordsFailed[rand.Intn(len(StatusWordsFailed))]
+	case StepSkipped:
+		return StatusWordSkipped
 	default:
 		return StatusWordPending
 	}
@@ -218,6 +230,8 @@ func (s *Sequence) getStepIcon(status StepStatus) string {
 		return "" // No icon - rely on emoji and colors
 	case StepFailed:
 		return "❌" // Red X for failures
+	case StepSkipped:
+		return "↷" // Skipped (inactive branch)
 	default:
 		return "•" // Pending dot
 	}
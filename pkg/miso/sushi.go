@@ -75,3 +75,6 @@ var StatusWordsFailed = []string{
 
 // StatusWordPending is the status word for pending nodes.
 const StatusWordPending = "Preparing"
+
+// StatusWordSkipped is the status word for nodes on an inactive branch.
+const StatusWordSkipped = "Skipped"
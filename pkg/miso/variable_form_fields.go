@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Develonaut/bento/pkg/neta"
+	"github.com/Develonaut/bento/pkg/omakase"
 	"github.com/charmbracelet/huh"
 )
 
@@ -31,7 +33,8 @@ func loadVariableDefault(v Variable) string {
 }
 
 // buildTextInputField creates a text input field for a variable.
-func buildTextInputField(title string, defaultValue string, valueHolder *string) huh.Field {
+// Input is type-checked against the variable's declared type.
+func buildTextInputField(v Variable, title string, defaultValue string, valueHolder *string) huh.Field {
 	if defaultValue != "" {
 		*valueHolder = defaultValue
 	}
@@ -41,10 +44,32 @@ func buildTextInputField(title string, defaultValue string, valueHolder *string)
 		placeholder = fmt.Sprintf("Enter %s", title)
 	}
 
-	return huh.NewInput().
+	field := huh.NewInput().
 		Title(title).
 		Placeholder(placeholder).
+		Validate(variableValidator(v)).
 		Value(valueHolder)
+
+	if v.Description != "" {
+		field = field.Description(v.Description)
+	}
+	return field
+}
+
+// variableValidator returns a form validator that checks input the same way
+// the run does (required and declared type).
+func variableValidator(v Variable) func(string) error {
+	decl := neta.Variable{Name: v.Name, Type: v.Type, Options: v.Options}
+	return func(value string) error {
+		if value == "" {
+			if v.Required {
+				return fmt.Errorf("%s is required", FormatVariableName(v.Name))
+			}
+			return nil
+		}
+		_, err := omakase.ResolveVariables([]neta.Variable{decl}, map[string]interface{}{v.Name: value})
+		return err
+	}
 }
 
 // buildFieldWithHeight creates a Huh input field with custom height for file pickers.
@@ -56,14 +81,20 @@ func buildFieldWithHeight(v Variable, valueHolder *string, terminalHeight int) h
 		return buildSelectField(v, title, valueHolder)
 	}
 
+	// Booleans are a true/false select
+	if v.Type == neta.VariableBoolean {
+		v.Options = []string{"true", "false"}
+		return buildSelectField(v, title, valueHolder)
+	}
+
 	// Check if this is a path/directory variable
-	if isPathVariable(v.Name) {
+	if v.Type == neta.VariablePath || isPathVariable(v.Name) {
 		return buildFilePickerFieldWithHeight(v, title, valueHolder, terminalHeight)
 	}
 
 	// Regular text input for non-path variables
 	defaultValue := loadVariableDefault(v)
-	return buildTextInputField(title, defaultValue, valueHolder)
+	return buildTextInputField(v, title, defaultValue, valueHolder)
 }
 
 // formatSelectOptions converts option strings to huh.Option format.
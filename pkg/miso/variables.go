@@ -2,9 +2,12 @@ package miso
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 
+	"github.com/Develonaut/bento/pkg/neta"
+
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -16,6 +19,23 @@ type Variable struct {
 	DefaultValue string   `json:"defaultValue,omitempty"`
 	Type         string   `json:"type,omitempty"`
 	Options      []string `json:"options,omitempty"`
+	Required     bool     `json:"required,omitempty"`
+}
+
+// declaredVariable converts a bento's declared variable for the form.
+func declaredVariable(v neta.Variable) Variable {
+	defaultValue := ""
+	if def := v.DefaultOrLegacy(); def != nil {
+		defaultValue = fmt.Sprint(def)
+	}
+	return Variable{
+		Name:         v.Name,
+		Description:  v.Description,
+		DefaultValue: defaultValue,
+		Type:         v.Type,
+		Options:      v.Options,
+		Required:     v.Required,
+	}
 }
 
 // ExtractVariables finds all {{.VARIABLE}} placeholders in a bento JSON.
@@ -76,7 +96,7 @@ func ParseBentoMetadata(bentoJSON []byte) (*BentoMetadata, error) {
 		Metadata struct {
 			Description string `json:"description"`
 		} `json:"metadata"`
-		Variables []Variable `json:"variables"`
+		Variables []neta.Variable `json:"variables"`
 	}
 
 	var meta bentoMeta
@@ -84,9 +104,12 @@ func ParseBentoMetadata(bentoJSON []byte) (*BentoMetadata, error) {
 		return nil, err
 	}
 
-	// If bento has defined variables, use those (they may include type and options)
+	// If bento has declared variables, use those (they may include type and options)
 	// Otherwise, extract variables from template placeholders
-	vars := meta.Variables
+	vars := make([]Variable, 0, len(meta.Variables))
+	for _, v := range meta.Variables {
+		vars = append(vars, declaredVariable(v))
+	}
 	if len(vars) == 0 {
 		vars = ExtractVariables(bentoJSON)
 	}
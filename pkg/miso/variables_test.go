@@ -138,6 +138,33 @@ func TestParseBentoMetadata(t *testing.T) {
 	}
 }
 
+// TestParseBentoMetadataDeclared tests that declared variables are used with their defaults.
+func TestParseBentoMetadataDeclared(t *testing.T) {
+	bentoJSON := `{
+		"name": "Test Bento",
+		"variables": [
+			{"name": "ZOOM_MULTIPLIER", "type": "number", "default": 1.5, "required": true},
+			{"name": "RENDER_THEME", "type": "select", "defaultValue": "fire", "options": ["fire"]}
+		]
+	}`
+
+	meta, err := ParseBentoMetadata([]byte(bentoJSON))
+	if err != nil {
+		t.Fatalf("ParseBentoMetadata failed: %v", err)
+	}
+
+	if len(meta.Variables) != 2 {
+		t.Fatalf("Expected 2 variables, got %d", len(meta.Variables))
+	}
+	zoom := meta.Variables[0]
+	if zoom.DefaultValue != "1.5" || zoom.Type != "number" || !zoom.Required {
+		t.Errorf("Unexpected ZOOM_MULTIPLIER: %+v", zoom)
+	}
+	if meta.Variables[1].DefaultValue != "fire" {
+		t.Errorf("Expected legacy defaultValue 'fire', got '%s'", meta.Variables[1].DefaultValue)
+	}
+}
+
 // TestParseBentoMetadataInvalidJSON tests error handling.
 func TestParseBentoMetadataInvalidJSON(t *testing.T) {
 	_, err := ParseBentoMetadata([]byte("invalid json"))
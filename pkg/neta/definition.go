@@ -47,19 +47,30 @@ package neta
 // Definitions can be nested (for group neta) and form a tree structure
 // representing the workflow hierarchy.
 type Definition struct {
-	ID          string                 `json:"id"`                 // Unique identifier within workflow
-	Type        string                 `json:"type"`               // Neta type (http-request, loop, etc.)
-	Version     string                 `json:"version"`            // Schema version for compatibility
-	ParentID    *string                `json:"parentId,omitempty"` // Parent group ID (if nested)
-	Name        string                 `json:"name"`               // Human-readable name
-	Position    Position               `json:"position"`           // Visual editor position
-	Metadata    Metadata               `json:"metadata"`           // Additional metadata
-	Parameters  map[string]interface{} `json:"parameters"`         // Neta-specific configuration
-	Fields      *FieldsConfig          `json:"fields,omitempty"`   // Field configuration (for edit-fields)
-	InputPorts  []Port                 `json:"inputPorts"`         // Input connection points
-	OutputPorts []Port                 `json:"outputPorts"`        // Output connection points
-	Nodes       []Definition           `json:"nodes,omitempty"`    // Child nodes (for group neta)
-	Edges       []Edge                 `json:"edges,omitempty"`    // Connections between child nodes
+	ID          string                 `json:"id"`                  // Unique identifier within workflow
+	Type        string                 `json:"type"`                // Neta type (http-request, loop, etc.)
+	Version     string                 `json:"version"`             // Schema version for compatibility
+	ParentID    *string                `json:"parentId,omitempty"`  // Parent group ID (if nested)
+	Name        string                 `json:"name"`                // Human-readable name
+	Position    Position               `json:"position"`            // Visual editor position
+	Metadata    Metadata               `json:"metadata"`            // Additional metadata
+	Parameters  map[string]interface{} `json:"parameters"`          // Neta-specific configuration
+	Fields      *FieldsConfig          `json:"fields,omitempty"`    // Field configuration (for edit-fields)
+	InputPorts  []Port                 `json:"inputPorts"`          // Input connection points
+	OutputPorts []Port                 `json:"outputPorts"`         // Output connection points
+	Nodes       []Definition           `json:"nodes,omitempty"`     // Child nodes (for group neta)
+	Edges       []Edge                 `json:"edges,omitempty"`     // Connections between child nodes
+	Catch       []Definition           `json:"catch,omitempty"`     // Nodes run when a group child fails (for group neta)
+	OnCancel    []Definition           `json:"onCancel,omitempty"`  // Nodes run when the run is cancelled (for group neta)
+	Finally     []Definition           `json:"finally,omitempty"`   // Nodes always run after a group (for group neta)
+	Outputs     map[string]interface{} `json:"outputs,omitempty"`   // Values returned when called as a sub-bento
+	Variables   []Variable             `json:"variables,omitempty"` // Declared inputs (root bento)
+	Retry       *RetryPolicy           `json:"retry,omitempty"`     // Retry policy for failed executions
+	Timeout     string                 `json:"timeout,omitempty"`   // Execution time limit enforced by itamae (e.g. "5m")
+	Cache       bool                   `json:"cache,omitempty"`     // Reuse cached output when inputs are unchanged
+	When        string                 `json:"when,omitempty"`      // expr-lang condition; the node is skipped when false
+	Resources   map[string]int         `json:"resources,omitempty"` // Named concurrency limits shared by the whole run (root bento)
+	Uses        string                 `json:"uses,omitempty"`      // Resource the node holds a slot of while it executes
 }
 
 // Position represents the visual location of a neta in the editor.
@@ -87,18 +98,22 @@ type Metadata struct {
 //
 // Ports are the attachment points where edges connect. A neta can have
 // multiple input and output ports, allowing complex data flow patterns.
+// An output port exposes one field of the neta's output (the whole output
+// when Field is empty).
 //
 // Example:
 //
 //	port := Port{
-//	    ID:     "input-1",
-//	    Name:   "Data Input",
+//	    ID:     "rows",
+//	    Name:   "Rows",
 //	    Handle: "source",
+//	    Field:  "rows",
 //	}
 type Port struct {
 	ID     string `json:"id"`               // Unique port identifier within neta
 	Name   string `json:"name"`             // Human-readable port name
 	Handle string `json:"handle,omitempty"` // Handle type (for visual editor)
+	Field  string `json:"field,omitempty"`  // Output field exposed by an output port (dot path)
 }
 
 // Edge represents a connection between two neta.
@@ -106,6 +121,11 @@ type Port struct {
 // Edges define the data flow in a workflow. Data flows from the source
 // neta's output port to the target neta's input port.
 //
+// An edge with a TargetHandle passes the source output field selected by
+// SourceHandle (an output port ID or a field path) to the target as the
+// named input params["_inputs"][TargetHandle]. Edges leaving branch neta
+// (if, switch) use SourceHandle to select the branch instead.
+//
 // Example:
 //
 //	edge := Edge{
@@ -123,6 +143,79 @@ type Edge struct {
 	TargetHandle string `json:"targetHandle,omitempty"` // Target port handle
 }
 
+// RetryPolicy configures how a failed neta execution is retried.
+//
+// The itamae enforces the policy for every neta type. Delays grow
+// exponentially (backoff, 2*backoff, 4*backoff, ...) up to maxDelay.
+//
+// Example:
+//
+//	"retry": {
+//	    "maxAttempts": 3,
+//	    "backoff": "2s",
+//	    "maxDelay": "30s",
+//	    "retryOn": {"errorPattern": "timeout|connection reset", "statusCodes": [429, 503]}
+//	}
+type RetryPolicy struct {
+	MaxAttempts int      `json:"maxAttempts"`        // Total attempts including the first
+	Backoff     string   `json:"backoff,omitempty"`  // Initial delay between attempts (default "1s")
+	MaxDelay    string   `json:"maxDelay,omitempty"` // Upper bound for the delay (default "30s")
+	RetryOn     *RetryOn `json:"retryOn,omitempty"`  // Which failures are retried (default: every error)
+}
+
+// RetryOn selects which failures a RetryPolicy retries.
+//
+// Errors are retried unless ErrorPattern is set and doesn't match.
+// Outputs with a "statusCode" listed in StatusCodes count as failures
+// (HTTP responses don't return errors for non-2xx codes).
+type RetryOn struct {
+	ErrorPattern string `json:"errorPattern,omitempty"` // Regular expression matched against the error message
+	StatusCodes  []int  `json:"statusCodes,omitempty"`  // Output status codes that are retried
+}
+
+// Variable types supported in a bento's variables section.
+const (
+	VariableString  = "string"  // Any text (default)
+	VariableNumber  = "number"  // Floating point number
+	VariableInteger = "integer" // Whole number
+	VariableBoolean = "boolean" // true or false
+	VariablePath    = "path"    // File or directory path (file picker in the TUI)
+	VariableSelect  = "select"  // One of Options
+)
+
+// Variable declares a bento input, available to templates as {{.NAME}}.
+//
+// Values come from `bento run --set NAME=value`, a vars file, the environment
+// or the TUI form, and are type-checked before the run starts.
+//
+// Example:
+//
+//	"variables": [
+//	    {"name": "ZOOM_MULTIPLIER", "type": "number", "default": 1.0},
+//	    {"name": "RENDER_THEME", "type": "select", "options": ["default|Default", "fire|Fire"]},
+//	    {"name": "PRODUCT_PATH", "type": "path", "required": true}
+//	]
+type Variable struct {
+	Name         string      `json:"name"`                   // Template name (e.g. PRODUCT_PATH)
+	Type         string      `json:"type,omitempty"`         // Value type (default "string")
+	Default      interface{} `json:"default,omitempty"`      // Value used when none is given
+	DefaultValue string      `json:"defaultValue,omitempty"` // Deprecated: use Default
+	Required     bool        `json:"required,omitempty"`     // Run fails before starting without a value
+	Options      []string    `json:"options,omitempty"`      // Allowed values for select ("value" or "value|label")
+	Description  string      `json:"description,omitempty"`  // Help text shown in forms
+}
+
+// DefaultOrLegacy returns Default, falling back to the deprecated DefaultValue.
+func (v Variable) DefaultOrLegacy() interface{} {
+	if v.Default != nil {
+		return v.Default
+	}
+	if v.DefaultValue != "" {
+		return v.DefaultValue
+	}
+	return nil
+}
+
 // FieldsConfig represents field editor configuration for edit-fields neta.
 //
 // The edit-fields neta uses this configuration to set field values,
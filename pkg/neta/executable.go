@@ -54,6 +54,7 @@ type Executable interface {
 	// The params map typically contains:
 	//   - Neta-specific configuration (from Definition.Parameters)
 	//   - "_context" key with accumulated data from previous neta
+	//   - "_inputs" key with named inputs from port edges (if connected)
 	//   - Other special keys prefixed with "_" (e.g., "_onOutput" for streaming)
 	//
 	// Returns:
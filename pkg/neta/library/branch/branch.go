@@ -0,0 +1,154 @@
+// Package branch provides conditional routing neta (if and switch).
+//
+// A branch neta evaluates an expression and returns the name of the output
+// handle that should stay active. The itamae only follows outgoing edges whose
+// sourceHandle matches that handle; nodes reachable only through inactive
+// handles are skipped. When no edge has the returned handle, only the edges
+// of the "default" handle are followed.
+//
+// # If
+//
+// The "if" neta evaluates a boolean condition and activates the "true" or
+// "false" handle:
+//
+//	params := map[string]interface{}{
+//	    "condition": "item.overlay != ''",
+//	    "_context":  map[string]interface{}{"item": map[string]interface{}{"overlay": "0.png"}},
+//	}
+//	result, _ := branch.NewIf().Execute(ctx, params)
+//	// result: {"handle": "true", "result": true}
+//
+// # Switch
+//
+// The "switch" neta evaluates an expression and activates the handle whose
+// name equals the result. If "cases" is given and the result isn't one of
+// them, the "default" handle is activated:
+//
+//	params := map[string]interface{}{
+//	    "expression": "item.category",
+//	    "cases":      []interface{}{"miniature", "terrain"},
+//	}
+//
+// Learn more about expr: https://github.com/expr-lang/expr
+package branch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/expr-lang/expr"
+)
+
+// DefaultHandle is activated by switch when no case matches.
+const DefaultHandle = "default"
+
+// If implements the if neta (boolean routing).
+type If struct{}
+
+// NewIf creates a new if neta instance.
+func NewIf() *If {
+	return &If{}
+}
+
+// Execute evaluates the condition and selects the "true" or "false" handle.
+//
+// Parameters:
+//   - condition: expr expression (or a boolean already resolved from a template)
+//   - _context: execution context with data
+//
+// Returns a map with:
+//   - handle: "true" or "false"
+//   - result: the boolean result
+func (n *If) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	var result bool
+	switch condition := params["condition"].(type) {
+	case bool:
+		result = condition
+	case string:
+		value, err := evaluate(condition, params)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("condition must evaluate to boolean, got %T", value)
+		}
+		result = b
+	default:
+		return nil, fmt.Errorf("condition parameter is required and must be a string or boolean")
+	}
+
+	return map[string]interface{}{
+		"handle": fmt.Sprintf("%t", result),
+		"result": result,
+	}, nil
+}
+
+// Switch implements the switch neta (multi-way routing).
+type Switch struct{}
+
+// NewSwitch creates a new switch neta instance.
+func NewSwitch() *Switch {
+	return &Switch{}
+}
+
+// Execute evaluates the expression and selects the matching handle.
+//
+// Parameters:
+//   - expression: expr expression whose result names the active handle
+//   - cases: optional list of known handles (others fall back to "default")
+//   - _context: execution context with data
+//
+// Returns a map with:
+//   - handle: the active handle
+//   - result: the raw expression result
+func (n *Switch) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	expression, ok := params["expression"].(string)
+	if !ok || expression == "" {
+		return nil, fmt.Errorf("expression parameter is required and must be a string")
+	}
+
+	value, err := evaluate(expression, params)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := fmt.Sprintf("%v", value)
+	if cases, ok := params["cases"].([]interface{}); ok && !containsCase(cases, handle) {
+		handle = DefaultHandle
+	}
+
+	return map[string]interface{}{
+		"handle": handle,
+		"result": value,
+	}, nil
+}
+
+// evaluate compiles and runs an expr expression against the _context param.
+func evaluate(expression string, params map[string]interface{}) (interface{}, error) {
+	env, _ := params["_context"].(map[string]interface{})
+	if env == nil {
+		env = make(map[string]interface{})
+	}
+
+	program, err := expr.Compile(expression, expr.Env(env))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile expression: %w", err)
+	}
+
+	value, err := expr.Run(program, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+	return value, nil
+}
+
+// containsCase reports whether handle is one of the declared cases.
+func containsCase(cases []interface{}, handle string) bool {
+	for _, c := range cases {
+		if fmt.Sprintf("%v", c) == handle {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package branch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Develonaut/bento/pkg/neta/library/branch"
+)
+
+// TestIf_Condition tests that the condition selects the true/false handle.
+func TestIf_Condition(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		condition interface{}
+		want      string
+	}{
+		{name: "true expression", condition: "count > 1", want: "true"},
+		{name: "false expression", condition: "count > 5", want: "false"},
+		{name: "resolved boolean", condition: false, want: "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := branch.NewIf().Execute(ctx, map[string]interface{}{
+				"condition": tt.condition,
+				"_context":  map[string]interface{}{"count": 3},
+			})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			output := result.(map[string]interface{})
+			if output["handle"] != tt.want {
+				t.Errorf("handle = %v, want %v", output["handle"], tt.want)
+			}
+		})
+	}
+}
+
+// TestIf_NonBoolean tests that non-boolean conditions are rejected.
+func TestIf_NonBoolean(t *testing.T) {
+	_, err := branch.NewIf().Execute(context.Background(), map[string]interface{}{
+		"condition": "1 + 1",
+	})
+	if err == nil {
+		t.Fatal("Expected error for non-boolean condition")
+	}
+}
+
+// TestSwitch_Cases tests case matching and the default handle.
+func TestSwitch_Cases(t *testing.T) {
+	ctx := context.Background()
+	cases := []interface{}{"miniature", "terrain"}
+
+	tests := []struct {
+		category string
+		want     string
+	}{
+		{category: "terrain", want: "terrain"},
+		{category: "poster", want: branch.DefaultHandle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			result, err := branch.NewSwitch().Execute(ctx, map[string]interface{}{
+				"expression": "category",
+				"cases":      cases,
+				"_context":   map[string]interface{}{"category": tt.category},
+			})
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+
+			output := result.(map[string]interface{})
+			if output["handle"] != tt.want {
+				t.Errorf("handle = %v, want %v", output["handle"], tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,10 @@
 //	"{{.product.name}}"           // Access nested fields
 //	"product-{{.product.id}}.png" // Mix static text and templates
 //	"{{index .items 0}}"          // Access array elements
+//	"{{.product.name | slug}}"    // Shape values with template functions
+//
+// Templates share the function set of parameter templates (see package
+// furikake), so a template behaves the same in both places.
 //
 // Learn more: https://pkg.go.dev/text/template
 //
@@ -49,6 +53,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/Develonaut/bento/pkg/furikake"
 	"github.com/Develonaut/bento/pkg/neta"
 )
 
@@ -163,7 +168,7 @@ func isTemplate(s string) bool {
 // executeTemplate executes a Go template string against the given context.
 func executeTemplate(tmplStr string, context map[string]interface{}) (string, error) {
 	// Parse template
-	tmpl, err := template.New("field").Parse(tmplStr)
+	tmpl, err := template.New("field").Funcs(furikake.Funcs()).Parse(tmplStr)
 	if err != nil {
 		return "", fmt.Errorf("template parse error: %w", err)
 	}
@@ -236,3 +236,35 @@ func TestEditFields_InvalidTemplateString(t *testing.T) {
 		t.Fatal("Expected error for invalid template syntax")
 	}
 }
+
+// TestEditFields_TemplateFunctions verifies templates can use the shared
+// template functions, e.g. building a file name from product data.
+func TestEditFields_TemplateFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	ef := editfields.New()
+
+	params := map[string]interface{}{
+		"values": map[string]interface{}{
+			"file":  "{{.product.name | slug}}-{{.index | padLeft 3 \"0\"}}.png",
+			"color": "{{.product.color | default \"red\"}}",
+		},
+		"_context": map[string]interface{}{
+			"product": map[string]interface{}{"name": "Combat Dog (Supplies)"},
+			"index":   7,
+		},
+	}
+
+	result, err := ef.Execute(ctx, params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	output := result.(map[string]interface{})
+	if output["file"] != "combat-dog-supplies-007.png" {
+		t.Errorf("file = %v, want combat-dog-supplies-007.png", output["file"])
+	}
+	if output["color"] != "red" {
+		t.Errorf("color = %v, want red", output["color"])
+	}
+}
@@ -31,6 +31,7 @@ package http
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/Develonaut/bento/pkg/neta"
 )
@@ -55,7 +56,7 @@ func New() neta.Executable {
 //   - method (string, required): HTTP method (GET, POST, PUT, DELETE, etc.)
 //   - headers (map[string]interface{}, optional): Custom headers
 //   - body (map[string]interface{}, optional): Request body (will be JSON encoded)
-//   - timeout (int, optional): Request timeout in seconds (default: 30)
+//   - timeout (number, optional): Request timeout in seconds (default: 30)
 //   - saveToFile (string, optional): Path to save response body to file (skips JSON parsing)
 //   - queryParams (map[string]interface{}, optional): URL query parameters to append
 //
@@ -104,10 +105,7 @@ func extractRequestParams(params map[string]interface{}) (*requestParams, error)
 		return nil, fmt.Errorf("method parameter is required and must be a string")
 	}
 
-	timeout := DefaultTimeout
-	if t, ok := params["timeout"].(int); ok {
-		timeout = t
-	}
+	timeout := extractTimeout(params)
 
 	saveToFile, _ := params["saveToFile"].(string)
 
@@ -118,3 +116,18 @@ func extractRequestParams(params map[string]interface{}) (*requestParams, error)
 		saveToFile: saveToFile,
 	}, nil
 }
+
+// extractTimeout extracts timeout value, handling int, float64 (JSON), and string from templates.
+func extractTimeout(params map[string]interface{}) int {
+	switch t := params["timeout"].(type) {
+	case int:
+		return t
+	case float64:
+		return int(t)
+	case string:
+		if timeout, err := strconv.Atoi(t); err == nil {
+			return timeout
+		}
+	}
+	return DefaultTimeout
+}
@@ -195,6 +195,32 @@ func TestHTTPRequest_Timeout(t *testing.T) {
 	}
 }
 
+// TestHTTPRequest_TimeoutFloat tests that JSON-decoded (float64) timeouts are honoured.
+func TestHTTPRequest_TimeoutFloat(t *testing.T) {
+	ctx := context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(3 * time.Second)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	params := map[string]interface{}{
+		"url":     ts.URL,
+		"method":  "GET",
+		"timeout": float64(1), // As produced by encoding/json
+	}
+
+	start := time.Now()
+	_, err := httpneta.New().Execute(ctx, params)
+	if err == nil {
+		t.Fatal("Expected timeout error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Request took %v, float64 timeout was ignored", elapsed)
+	}
+}
+
 // TestHTTPRequest_4xxError tests handling of 4xx client errors.
 func TestHTTPRequest_4xxError(t *testing.T) {
 	ctx := context.Background()
@@ -0,0 +1,57 @@
+package shellcommand
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+)
+
+// running holds the commands in flight, so KillRunning can reach them.
+var running = struct {
+	sync.Mutex
+	cmds map[*exec.Cmd]struct{}
+}{cmds: make(map[*exec.Cmd]struct{})}
+
+// startCommand starts cmd in its own process group, so stopping it also
+// stops the processes it spawns (e.g. Blender's workers). Once cmdCtx is
+// done the group is asked to stop (SIGTERM); waitCommand kills what's left.
+func startCommand(cmd *exec.Cmd) error {
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return terminateGroup(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	running.Lock()
+	running.cmds[cmd] = struct{}{}
+	running.Unlock()
+	return nil
+}
+
+// waitCommand waits for a started command. When cmdCtx is done, the
+// processes of its group that didn't stop within OutputWaitDelay of the
+// SIGTERM are killed (SIGKILL).
+func waitCommand(cmdCtx context.Context, cmd *exec.Cmd) error {
+	err := cmd.Wait()
+	if cmdCtx.Err() != nil {
+		_ = killGroup(cmd)
+	}
+
+	running.Lock()
+	delete(running.cmds, cmd)
+	running.Unlock()
+	return err
+}
+
+// KillRunning kills the process groups of all running commands. Call it
+// before exiting without waiting for the run to stop, or they outlive bento.
+func KillRunning() {
+	running.Lock()
+	defer running.Unlock()
+	for cmd := range running.cmds {
+		_ = killGroup(cmd)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package shellcommand
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows, which has no process groups to signal.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateGroup kills the command (Windows has no SIGTERM).
+func terminateGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killGroup kills the command if it is still running.
+func killGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -38,10 +38,10 @@
 package shellcommand
 
 import (
-	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -55,6 +55,12 @@ const (
 	// Set to 2 minutes for typical commands, but can be overridden
 	// for long-running operations like Blender renders (30+ minutes).
 	DefaultTimeout = 120
+
+	// OutputWaitDelay is how long a command's output is still read after it
+	// exits or is cancelled. Processes it started (e.g. Blender workers) that
+	// keep its output open can't hold up the node any longer. A cancelled
+	// command's processes get as long to stop after SIGTERM before SIGKILL.
+	OutputWaitDelay = 5 * time.Second
 )
 
 // ShellCommandNeta implements shell command execution.
@@ -97,6 +103,7 @@ func (s *ShellCommandNeta) Execute(ctx context.Context, params map[string]interf
 	defer cancel()
 
 	cmd := exec.CommandContext(cmdCtx, cmdParams.command, cmdParams.args...)
+	cmd.WaitDelay = OutputWaitDelay
 
 	if cmdParams.stream && cmdParams.onOutput != nil {
 		return s.executeStreaming(cmdCtx, cmd, cmdParams)
@@ -171,41 +178,58 @@ func (s *ShellCommandNeta) extractTimeout(params map[string]interface{}) int {
 // executeStreaming runs a command with streaming output.
 func (s *ShellCommandNeta) executeStreaming(cmdCtx context.Context, cmd *exec.Cmd, params *commandParams) (interface{}, error) {
 	var stdoutBuilder, stderrBuilder strings.Builder
+	stdout := &lineWriter{builder: &stdoutBuilder, onLine: params.onOutput}
+	stderr := &lineWriter{builder: &stderrBuilder}
 
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
+	// Wait copies the output until the command's pipes close (bounded by WaitDelay)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
+	if err := startCommand(cmd); err != nil {
 		return nil, fmt.Errorf("failed to start command: %w", err)
 	}
 
-	s.streamOutput(stdoutPipe, &stdoutBuilder, params.onOutput)
-	s.streamOutput(stderrPipe, &stderrBuilder, nil)
-
-	err = cmd.Wait()
+	err := waitCommand(cmdCtx, cmd)
+	stdout.flush()
+	stderr.flush()
 	return s.handleCommandResult(cmdCtx, err, &stdoutBuilder, &stderrBuilder, params.timeout)
 }
 
-// streamOutput reads from a pipe line-by-line and optionally calls a callback.
-func (s *ShellCommandNeta) streamOutput(pipe io.ReadCloser, builder *strings.Builder, callback func(string)) {
-	scanner := bufio.NewScanner(pipe)
-	go func() {
-		for scanner.Scan() {
-			line := scanner.Text()
-			builder.WriteString(line)
-			builder.WriteString("\n")
-			if callback != nil {
-				callback(line)
-			}
+// lineWriter collects a command's output and calls onLine for each line.
+type lineWriter struct {
+	builder *strings.Builder
+	onLine  func(string)
+	partial []byte // Output after the last newline
+}
+
+// Write adds output, reporting every line it completes.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			return len(p), nil
 		}
-	}()
+		w.line(strings.TrimSuffix(string(w.partial[:idx]), "\r"))
+		w.partial = w.partial[idx+1:]
+	}
+}
+
+// flush reports the output after the last newline as a final line.
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.line(string(w.partial))
+		w.partial = nil
+	}
+}
+
+// line records one line of output.
+func (w *lineWriter) line(line string) {
+	w.builder.WriteString(line)
+	w.builder.WriteString("\n")
+	if w.onLine != nil {
+		w.onLine(line)
+	}
 }
 
 // executeBuffered runs a command with buffered output.
@@ -215,7 +239,10 @@ func (s *ShellCommandNeta) executeBuffered(cmdCtx context.Context, cmd *exec.Cmd
 	cmd.Stdout = &stdoutBuilder
 	cmd.Stderr = &stderrBuilder
 
-	err := cmd.Run()
+	err := startCommand(cmd)
+	if err == nil {
+		err = waitCommand(cmdCtx, cmd)
+	}
 	return s.handleCommandResult(cmdCtx, err, &stdoutBuilder, &stderrBuilder, timeout)
 }
 
@@ -230,6 +257,10 @@ func (s *ShellCommandNeta) handleCommandResult(cmdCtx context.Context, err error
 	}
 
 	exitCode := 0
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// The command succeeded; processes it left running kept its output open
+		err = nil
+	}
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exitCode = exitErr.ExitCode()
@@ -2,6 +2,7 @@ package shellcommand_test
 
 import (
 	"context"
+	"os"
 	"runtime"
 	"strings"
 	"testing"
@@ -379,3 +380,72 @@ func TestShellCommand_DefaultTimeout(t *testing.T) {
 		t.Errorf("exitCode = %v, want 0", output["exitCode"])
 	}
 }
+
+// TestShellCommand_StreamingLeftoverProcess tests that a process left running
+// by the command, holding its output open, doesn't block the node.
+func TestShellCommand_StreamingLeftoverProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Uses a POSIX shell")
+	}
+
+	var lines []string
+	params := map[string]interface{}{
+		"command":   "sh",
+		"args":      []interface{}{"-c", "sleep 30 & echo rendered"},
+		"stream":    true,
+		"_onOutput": func(line string) { lines = append(lines, line) },
+	}
+
+	start := time.Now()
+	result, err := shellcommand.New().Execute(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > shellcommand.OutputWaitDelay+2*time.Second {
+		t.Errorf("Execute took %v, waited for the leftover process", elapsed)
+	}
+
+	output := result.(map[string]interface{})
+	if output["stdout"] != "rendered\n" || len(lines) != 1 || lines[0] != "rendered" {
+		t.Errorf("stdout = %q, lines = %v, want the command's line", output["stdout"], lines)
+	}
+}
+
+// TestShellCommand_CancelStopsSpawnedProcesses tests that cancelling a
+// command also stops the processes it spawned.
+func TestShellCommand_CancelStopsSpawnedProcesses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Reads process state from /proc")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pids := make(chan string, 1)
+	params := map[string]interface{}{
+		"command":   "sh",
+		"args":      []interface{}{"-c", "sleep 30 & echo $!; wait"},
+		"stream":    true,
+		"_onOutput": func(line string) { pids <- line },
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := shellcommand.New().Execute(ctx, params)
+		done <- err
+	}()
+
+	pid := <-pids
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(shellcommand.OutputWaitDelay + 2*time.Second):
+		t.Fatal("Execute did not return after cancellation")
+	}
+
+	// Killed processes disappear, or stay zombies until reaped
+	stat, err := os.ReadFile("/proc/" + pid + "/stat")
+	if err == nil && !strings.Contains(string(stat), ") Z ") {
+		t.Errorf("spawned process %s still running: %s", pid, stat)
+	}
+}
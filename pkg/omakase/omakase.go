@@ -20,7 +20,7 @@
 //	}
 //
 //	// Pre-flight checks (environment, commands, API keys)
-//	if err := validator.PreflightCheck(ctx, netaDef); err != nil {
+//	if err := validator.PreflightCheck(ctx, netaDef, vars); err != nil {
 //	    log.Fatalf("Pre-flight check failed: %v", err)
 //	}
 //
@@ -60,6 +60,9 @@ func New() *Validator {
 	v.validators["spreadsheet"] = validateSpreadsheet
 	v.validators["image"] = validateImage
 	v.validators["transform"] = validateTransform
+	v.validators["if"] = validateIf
+	v.validators["switch"] = validateSwitch
+	v.validators["bento"] = validateSubBento
 
 	return v
 }
@@ -69,6 +72,16 @@ func New() *Validator {
 // Returns a clear, actionable error message if validation fails.
 // The error message always includes the neta ID for debugging.
 func (v *Validator) Validate(ctx context.Context, def *neta.Definition) error {
+	if err := v.validate(ctx, def); err != nil {
+		return err
+	}
+
+	// Resources are declared once, for the whole bento
+	return validateResourceUses(def, def.Resources)
+}
+
+// validate validates a neta definition and its nested nodes.
+func (v *Validator) validate(ctx context.Context, def *neta.Definition) error {
 	// Check for cancellation
 	if ctx.Err() != nil {
 		return ctx.Err()
@@ -97,6 +110,30 @@ func (v *Validator) validateSpecificAndNested(ctx context.Context, def *neta.Def
 		return err
 	}
 
+	if err := validateRetry(def); err != nil {
+		return err
+	}
+
+	if err := validateTimeout(def); err != nil {
+		return err
+	}
+
+	if err := validateWhen(def); err != nil {
+		return err
+	}
+
+	if err := validateVariables(def); err != nil {
+		return err
+	}
+
+	if err := validateResources(def); err != nil {
+		return err
+	}
+
+	if def.Type != "group" && (len(def.Catch) > 0 || len(def.OnCancel) > 0 || len(def.Finally) > 0) {
+		return fmt.Errorf("neta '%s' has catch/onCancel/finally nodes but only group neta support them", def.ID)
+	}
+
 	if def.Type == "group" {
 		return v.validateGroup(ctx, def)
 	}
@@ -149,13 +186,28 @@ func (v *Validator) validateGroup(ctx context.Context, def *neta.Definition) err
 	return v.validateEdges(def)
 }
 
-// validateChildNodes validates all child nodes in a group.
+// validateChildNodes validates all child, catch, onCancel and finally nodes in a group.
 func (v *Validator) validateChildNodes(ctx context.Context, def *neta.Definition) error {
 	for _, child := range def.Nodes {
-		if err := v.Validate(ctx, &child); err != nil {
+		if err := v.validate(ctx, &child); err != nil {
 			return fmt.Errorf("invalid child node in group '%s': %w", def.ID, err)
 		}
 	}
+	for _, child := range def.Catch {
+		if err := v.validate(ctx, &child); err != nil {
+			return fmt.Errorf("invalid catch node in group '%s': %w", def.ID, err)
+		}
+	}
+	for _, child := range def.OnCancel {
+		if err := v.validate(ctx, &child); err != nil {
+			return fmt.Errorf("invalid onCancel node in group '%s': %w", def.ID, err)
+		}
+	}
+	for _, child := range def.Finally {
+		if err := v.validate(ctx, &child); err != nil {
+			return fmt.Errorf("invalid finally node in group '%s': %w", def.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -173,6 +225,74 @@ func (v *Validator) validateEdges(def *neta.Definition) error {
 				edge.ID, def.ID, edge.Target)
 		}
 	}
+	if err := validateIfHandles(def); err != nil {
+		return err
+	}
+	return validatePorts(def)
+}
+
+// validatePorts validates that edge handles reference ports declared on the
+// connected neta, and that each input port is fed by at most one edge.
+// Edges leaving branch neta (if, switch) route by sourceHandle instead.
+func validatePorts(def *neta.Definition) error {
+	nodes := make(map[string]*neta.Definition, len(def.Nodes))
+	for idx := range def.Nodes {
+		nodes[def.Nodes[idx].ID] = &def.Nodes[idx]
+	}
+
+	connected := make(map[string]string) // "target/input" -> edge ID
+	for _, edge := range def.Edges {
+		source, target := nodes[edge.Source], nodes[edge.Target]
+		if edge.SourceHandle != "" && source.Type != "if" && source.Type != "switch" &&
+			!hasPort(source.OutputPorts, edge.SourceHandle) {
+			return fmt.Errorf("edge '%s' in group '%s' has invalid sourceHandle '%s' (neta '%s' has no such output port)",
+				edge.ID, def.ID, edge.SourceHandle, edge.Source)
+		}
+		if edge.TargetHandle == "" {
+			continue
+		}
+		if !hasPort(target.InputPorts, edge.TargetHandle) {
+			return fmt.Errorf("edge '%s' in group '%s' has invalid targetHandle '%s' (neta '%s' has no such input port)",
+				edge.ID, def.ID, edge.TargetHandle, edge.Target)
+		}
+		key := edge.Target + "/" + edge.TargetHandle
+		if other, ok := connected[key]; ok {
+			return fmt.Errorf("edges '%s' and '%s' in group '%s' both connect to input '%s' of neta '%s'",
+				other, edge.ID, def.ID, edge.TargetHandle, edge.Target)
+		}
+		connected[key] = edge.ID
+	}
+	return nil
+}
+
+// hasPort reports whether a port with the given ID is declared.
+func hasPort(ports []neta.Port, id string) bool {
+	for _, port := range ports {
+		if port.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIfHandles validates that edges leaving an if neta use the "true" or "false" handle.
+func validateIfHandles(def *neta.Definition) error {
+	ifNodes := make(map[string]bool)
+	for _, node := range def.Nodes {
+		if node.Type == "if" {
+			ifNodes[node.ID] = true
+		}
+	}
+
+	for _, edge := range def.Edges {
+		if !ifNodes[edge.Source] {
+			continue
+		}
+		if edge.SourceHandle != "" && edge.SourceHandle != "true" && edge.SourceHandle != "false" {
+			return fmt.Errorf("edge '%s' in group '%s' has invalid sourceHandle '%s' (if neta only has 'true' and 'false')",
+				edge.ID, def.ID, edge.SourceHandle)
+		}
+	}
 	return nil
 }
 
@@ -189,48 +309,50 @@ func buildNodeIDMap(nodes []neta.Definition) map[string]bool {
 //
 // This includes:
 //   - Verifying required commands are installed
-//   - Checking environment variables exist
+//   - Checking variables used in templates have a value (vars, resolved
+//     from --set or a vars file, or the environment)
 //   - Validating file paths exist
 //   - Recursively checking child nodes in groups and loops
 //
 // Returns a clear error message if any pre-flight check fails.
-func (v *Validator) PreflightCheck(ctx context.Context, def *neta.Definition) error {
+func (v *Validator) PreflightCheck(ctx context.Context, def *neta.Definition, vars map[string]interface{}) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
 
 	// Type-specific preflight checks
-	if err := v.preflightTypeSpecific(def); err != nil {
+	if err := v.preflightTypeSpecific(def, vars); err != nil {
 		return err
 	}
 
 	// Recursive preflight for groups and loops
-	return v.preflightRecursive(ctx, def)
+	return v.preflightRecursive(ctx, def, vars)
 }
 
 // preflightTypeSpecific performs type-specific preflight checks.
-func (v *Validator) preflightTypeSpecific(def *neta.Definition) error {
+func (v *Validator) preflightTypeSpecific(def *neta.Definition, vars map[string]interface{}) error {
 	switch def.Type {
 	case "shell-command":
 		return preflightShellCommand(def)
 	case "http-request":
-		return preflightHTTPRequest(def)
+		return preflightHTTPRequest(def, vars)
 	case "file-system":
-		return preflightFileSystem(def)
+		return preflightFileSystem(def, vars)
 	case "spreadsheet":
-		return preflightSpreadsheet(def)
+		return preflightSpreadsheet(def, vars)
 	}
 	return nil
 }
 
 // preflightRecursive performs preflight checks on child nodes.
-func (v *Validator) preflightRecursive(ctx context.Context, def *neta.Definition) error {
+func (v *Validator) preflightRecursive(ctx context.Context, def *neta.Definition, vars map[string]interface{}) error {
 	if def.Type != "group" && def.Type != "loop" {
 		return nil
 	}
 
-	for _, child := range def.Nodes {
-		if err := v.PreflightCheck(ctx, &child); err != nil {
+	children := append(append(append(append([]neta.Definition{}, def.Nodes...), def.Catch...), def.OnCancel...), def.Finally...)
+	for _, child := range children {
+		if err := v.PreflightCheck(ctx, &child, vars); err != nil {
 			return fmt.Errorf("preflight check failed in '%s': %w", def.ID, err)
 		}
 	}
@@ -353,6 +353,61 @@ func TestValidator_InvalidEdgeTarget(t *testing.T) {
 	}
 }
 
+// TestValidator_EdgePorts tests that edge handles must reference declared ports.
+func TestValidator_EdgePorts(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	portGroup := func(sourceHandle, targetHandle string) *neta.Definition {
+		return &neta.Definition{
+			ID:      "group-1",
+			Type:    "group",
+			Version: "1.0.0",
+			Name:    "Main Group",
+			Nodes: []neta.Definition{
+				{
+					ID:          "read",
+					Type:        "spreadsheet",
+					Version:     "1.0.0",
+					Parameters:  map[string]interface{}{"operation": "read", "path": "products.csv"},
+					OutputPorts: []neta.Port{{ID: "rows", Name: "Rows", Field: "rows"}},
+				},
+				{
+					ID:         "render",
+					Type:       "shell-command",
+					Version:    "1.0.0",
+					Parameters: map[string]interface{}{"command": "blender"},
+					InputPorts: []neta.Port{{ID: "products", Name: "Products"}},
+				},
+			},
+			Edges: []neta.Edge{
+				{ID: "edge-1", Source: "read", Target: "render", SourceHandle: sourceHandle, TargetHandle: targetHandle},
+			},
+		}
+	}
+
+	if err := validator.Validate(ctx, portGroup("rows", "products")); err != nil {
+		t.Errorf("Expected declared ports to be valid, got %v", err)
+	}
+
+	err := validator.Validate(ctx, portGroup("columns", "products"))
+	if err == nil || !contains(err.Error(), "columns") {
+		t.Errorf("Expected error for unknown output port, got %v", err)
+	}
+
+	err = validator.Validate(ctx, portGroup("rows", "images"))
+	if err == nil || !contains(err.Error(), "images") {
+		t.Errorf("Expected error for unknown input port, got %v", err)
+	}
+
+	def := portGroup("rows", "products")
+	def.Edges = append(def.Edges, neta.Edge{ID: "edge-2", Source: "read", Target: "render", TargetHandle: "products"})
+	err = validator.Validate(ctx, def)
+	if err == nil || !contains(err.Error(), "edge-2") {
+		t.Errorf("Expected error for input connected twice, got %v", err)
+	}
+}
+
 // Test: Loop neta with invalid mode should fail
 func TestValidator_LoopInvalidMode(t *testing.T) {
 	validator := omakase.New()
@@ -385,6 +440,7 @@ func TestValidator_LoopValidModes(t *testing.T) {
 		value      interface{}
 	}{
 		{"forEach", "items", []string{"a", "b"}},
+		{"files", "pattern", "products/*/model.stl"},
 		{"times", "count", 5},
 		{"while", "condition", "x < 10"},
 	}
@@ -408,6 +464,71 @@ func TestValidator_LoopValidModes(t *testing.T) {
 	}
 }
 
+// Test: While loop interval must be a positive duration
+func TestValidator_LoopWhileInterval(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		interval interface{}
+		valid    bool
+	}{
+		{"30s", true},
+		{"soon", false},
+		{"0s", false},
+		{float64(30), false},
+	} {
+		def := &neta.Definition{
+			ID:      "node-1",
+			Type:    "loop",
+			Version: "1.0.0",
+			Name:    "Loop",
+			Parameters: map[string]interface{}{
+				"mode":      "while",
+				"condition": "x < 10",
+				"interval":  tc.interval,
+			},
+		}
+
+		err := validator.Validate(ctx, def)
+		if tc.valid && err != nil {
+			t.Errorf("interval %v should be valid: %v", tc.interval, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("interval %v should be invalid", tc.interval)
+		}
+	}
+}
+
+// Test: Parallel neta with an unknown errorStrategy should fail
+func TestValidator_ParallelErrorStrategy(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	for strategy, valid := range map[string]bool{
+		"failFast":   true,
+		"collectAll": true,
+		"bestEffort": true,
+		"ignore":     false,
+	} {
+		def := &neta.Definition{
+			ID:         "node-1",
+			Type:       "parallel",
+			Version:    "1.0.0",
+			Name:       "Parallel",
+			Parameters: map[string]interface{}{"errorStrategy": strategy},
+		}
+
+		err := validator.Validate(ctx, def)
+		if valid && err != nil {
+			t.Errorf("errorStrategy %s should be valid: %v", strategy, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("errorStrategy %s should be invalid", strategy)
+		}
+	}
+}
+
 // Test: File-system neta with invalid operation should fail
 func TestValidator_FileSystemInvalidOperation(t *testing.T) {
 	validator := omakase.New()
@@ -549,6 +670,226 @@ func TestValidator_InvalidChildNode(t *testing.T) {
 	}
 }
 
+// Test: Invalid retry backoff should fail
+func TestValidator_RetryInvalidBackoff(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	def := &neta.Definition{
+		ID:      "node-1",
+		Type:    "shell-command",
+		Version: "1.0.0",
+		Name:    "Render",
+		Parameters: map[string]interface{}{
+			"command": "blender",
+		},
+		Retry: &neta.RetryPolicy{MaxAttempts: 3, Backoff: "soon"},
+	}
+
+	err := validator.Validate(ctx, def)
+	if err == nil {
+		t.Fatal("Expected validation error for invalid backoff")
+	}
+
+	if !contains(err.Error(), "backoff") {
+		t.Errorf("Error should mention 'backoff': %s", err.Error())
+	}
+}
+
+// TestValidator_CatchOnlyOnGroup tests that catch/finally nodes require a group.
+func TestValidator_CatchOnlyOnGroup(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	def := &neta.Definition{
+		ID:      "node-1",
+		Type:    "shell-command",
+		Version: "1.0.0",
+		Name:    "Render",
+		Parameters: map[string]interface{}{
+			"command": "blender",
+		},
+		Finally: []neta.Definition{{ID: "cleanup", Type: "shell-command", Version: "1.0.0"}},
+	}
+
+	err := validator.Validate(ctx, def)
+	if err == nil {
+		t.Fatal("Expected validation error for finally on a non-group neta")
+	}
+
+	if !contains(err.Error(), "only group") {
+		t.Errorf("Error should mention 'only group': %s", err.Error())
+	}
+}
+
+// TestValidator_WhenCondition tests that when conditions must compile to a boolean.
+func TestValidator_WhenCondition(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	def := &neta.Definition{
+		ID:         "overlay",
+		Type:       "shell-command",
+		Version:    "1.0.0",
+		Name:       "Composite Overlay",
+		Parameters: map[string]interface{}{"command": "magick"},
+		When:       "item.overlay != nil",
+	}
+	if err := validator.Validate(ctx, def); err != nil {
+		t.Fatalf("Expected valid when condition, got: %v", err)
+	}
+
+	def.When = "item.overlay !="
+	err := validator.Validate(ctx, def)
+	if err == nil || !contains(err.Error(), "when condition is invalid") {
+		t.Errorf("Expected invalid when condition error, got: %v", err)
+	}
+}
+
+// TestValidator_Resources tests resource declarations and the nodes using them.
+func TestValidator_Resources(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	def := &neta.Definition{
+		ID:        "bento-1",
+		Type:      "group",
+		Version:   "1.0.0",
+		Name:      "Renders",
+		Resources: map[string]int{"blender": 1, "network": 8},
+		Nodes: []neta.Definition{{
+			ID:         "render",
+			Type:       "shell-command",
+			Version:    "1.0.0",
+			Name:       "Render",
+			Parameters: map[string]interface{}{"command": "blender"},
+			Uses:       "blender",
+		}},
+	}
+	if err := validator.Validate(ctx, def); err != nil {
+		t.Fatalf("Expected valid resources, got: %v", err)
+	}
+
+	def.Nodes[0].Uses = "gpu"
+	err := validator.Validate(ctx, def)
+	if err == nil || !contains(err.Error(), "undeclared resource 'gpu'") {
+		t.Errorf("Expected undeclared resource error, got: %v", err)
+	}
+
+	def.Nodes[0].Uses = "blender"
+	def.Resources["blender"] = 0
+	err = validator.Validate(ctx, def)
+	if err == nil || !contains(err.Error(), "must be at least 1") {
+		t.Errorf("Expected resource limit error, got: %v", err)
+	}
+}
+
+// TestValidator_VariableInvalidDefault tests that defaults must match the declared type.
+func TestValidator_VariableInvalidDefault(t *testing.T) {
+	validator := omakase.New()
+	ctx := context.Background()
+
+	def := &neta.Definition{
+		ID:      "bento-1",
+		Type:    "group",
+		Version: "1.0.0",
+		Name:    "Renders",
+		Variables: []neta.Variable{
+			{Name: "ZOOM_MULTIPLIER", Type: "number", Default: "wide"},
+		},
+	}
+
+	err := validator.Validate(ctx, def)
+	if err == nil {
+		t.Fatal("Expected validation error for non-numeric default")
+	}
+
+	if !contains(err.Error(), "ZOOM_MULTIPLIER") {
+		t.Errorf("Error should mention the variable: %s", err.Error())
+	}
+}
+
+// TestResolveVariables tests type conversion, defaults and required variables.
+func TestResolveVariables(t *testing.T) {
+	vars := []neta.Variable{
+		{Name: "PRODUCT_PATH", Type: "path", Required: true},
+		{Name: "ZOOM_MULTIPLIER", Type: "number", Default: 1.0},
+		{Name: "COPIES", Type: "integer"},
+		{Name: "TRANSPARENT", Type: "boolean", DefaultValue: "false"},
+		{Name: "RENDER_THEME", Type: "select", Options: []string{"default|Default", "fire|Fire"}},
+	}
+
+	resolved, err := omakase.ResolveVariables(vars, map[string]interface{}{
+		"PRODUCT_PATH": "./chair",
+		"COPIES":       "3",
+		"RENDER_THEME": "fire",
+	})
+	if err != nil {
+		t.Fatalf("ResolveVariables failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"PRODUCT_PATH":    "./chair",
+		"ZOOM_MULTIPLIER": 1.0,
+		"COPIES":          3,
+		"TRANSPARENT":     false,
+		"RENDER_THEME":    "fire",
+	}
+	for name, value := range want {
+		if resolved[name] != value {
+			t.Errorf("%s = %#v, want %#v", name, resolved[name], value)
+		}
+	}
+}
+
+// TestResolveVariables_Invalid tests that every bad value is reported before running.
+func TestResolveVariables_Invalid(t *testing.T) {
+	vars := []neta.Variable{
+		{Name: "PRODUCT_PATH", Type: "path", Required: true},
+		{Name: "COPIES", Type: "integer"},
+		{Name: "RENDER_THEME", Type: "select", Options: []string{"default", "fire"}},
+	}
+
+	_, err := omakase.ResolveVariables(vars, map[string]interface{}{
+		"COPIES":       "2.5",
+		"RENDER_THEME": "ice",
+	})
+	if err == nil {
+		t.Fatal("Expected error for invalid variables")
+	}
+
+	for _, name := range []string{"PRODUCT_PATH", "COPIES", "RENDER_THEME"} {
+		if !contains(err.Error(), name) {
+			t.Errorf("Error should mention %s: %s", name, err.Error())
+		}
+	}
+}
+
+// TestValidator_PreflightVariables tests that resolved variables satisfy
+// preflight checks of template paths, as environment variables do.
+func TestValidator_PreflightVariables(t *testing.T) {
+	validator := omakase.New()
+	def := &neta.Definition{
+		ID:      "write",
+		Type:    "file-system",
+		Version: "1.0.0",
+		Parameters: map[string]interface{}{
+			"operation": "write",
+			"path":      "{{.PREFLIGHT_OUTPUT_DIR}}/out.txt",
+		},
+	}
+
+	err := validator.PreflightCheck(context.Background(), def, nil)
+	if err == nil || !contains(err.Error(), "PREFLIGHT_OUTPUT_DIR") {
+		t.Errorf("error = %v, want the unset variable named", err)
+	}
+
+	vars := map[string]interface{}{"PREFLIGHT_OUTPUT_DIR": t.TempDir()}
+	if err := validator.PreflightCheck(context.Background(), def, vars); err != nil {
+		t.Errorf("PreflightCheck with the variable set failed: %v", err)
+	}
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
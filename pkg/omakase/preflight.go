@@ -27,21 +27,21 @@ func preflightShellCommand(def *neta.Definition) error {
 }
 
 // preflightHTTPRequest checks for required environment variables in URL/headers.
-func preflightHTTPRequest(def *neta.Definition) error {
-	if err := checkURLEnvVars(def); err != nil {
+func preflightHTTPRequest(def *neta.Definition, vars map[string]interface{}) error {
+	if err := checkURLEnvVars(def, vars); err != nil {
 		return err
 	}
-	return checkHeaderEnvVars(def)
+	return checkHeaderEnvVars(def, vars)
 }
 
 // checkURLEnvVars validates environment variables in URL.
-func checkURLEnvVars(def *neta.Definition) error {
+func checkURLEnvVars(def *neta.Definition, vars map[string]interface{}) error {
 	url, _ := def.Parameters["url"].(string)
 	envVars := extractEnvVars(url)
 
 	for _, envVar := range envVars {
-		if os.Getenv(envVar) == "" {
-			return fmt.Errorf("http-request neta '%s': environment variable '%s' not set (required in URL)",
+		if !varSet(vars, envVar) {
+			return fmt.Errorf("http-request neta '%s': variable '%s' not set (required in URL)",
 				def.ID, envVar)
 		}
 	}
@@ -49,14 +49,14 @@ func checkURLEnvVars(def *neta.Definition) error {
 }
 
 // checkHeaderEnvVars validates environment variables in headers.
-func checkHeaderEnvVars(def *neta.Definition) error {
+func checkHeaderEnvVars(def *neta.Definition, vars map[string]interface{}) error {
 	headers, ok := def.Parameters["headers"].(map[string]string)
 	if !ok {
 		return nil
 	}
 
 	for key, value := range headers {
-		if err := checkHeaderValue(def, key, value); err != nil {
+		if err := checkHeaderValue(def, vars, key, value); err != nil {
 			return err
 		}
 	}
@@ -64,11 +64,11 @@ func checkHeaderEnvVars(def *neta.Definition) error {
 }
 
 // checkHeaderValue validates environment variables in a single header value.
-func checkHeaderValue(def *neta.Definition, key, value string) error {
+func checkHeaderValue(def *neta.Definition, vars map[string]interface{}, key, value string) error {
 	envVars := extractEnvVars(value)
 	for _, envVar := range envVars {
-		if os.Getenv(envVar) == "" {
-			return fmt.Errorf("http-request neta '%s': environment variable '%s' not set (required in header '%s')",
+		if !varSet(vars, envVar) {
+			return fmt.Errorf("http-request neta '%s': variable '%s' not set (required in header '%s')",
 				def.ID, envVar, key)
 		}
 	}
@@ -76,7 +76,7 @@ func checkHeaderValue(def *neta.Definition, key, value string) error {
 }
 
 // preflightFileSystem checks if file paths exist for read operations.
-func preflightFileSystem(def *neta.Definition) error {
+func preflightFileSystem(def *neta.Definition, vars map[string]interface{}) error {
 	operation, _ := def.Parameters["operation"].(string)
 	path, ok := def.Parameters["path"].(string)
 	if !ok {
@@ -84,7 +84,7 @@ func preflightFileSystem(def *neta.Definition) error {
 	}
 
 	// Check environment variables in path first
-	if err := checkPathEnvVars(def, path); err != nil {
+	if err := checkPathEnvVars(def, vars, path); err != nil {
 		return err
 	}
 
@@ -98,7 +98,7 @@ func preflightFileSystem(def *neta.Definition) error {
 	// For copy operation, also check source path
 	if operation == "copy" {
 		if source, ok := def.Parameters["source"].(string); ok {
-			if err := checkPathEnvVars(def, source); err != nil {
+			if err := checkPathEnvVars(def, vars, source); err != nil {
 				return err
 			}
 			if !containsTemplates(source) {
@@ -168,7 +168,7 @@ func findNextVar(s string) (string, string, bool) {
 }
 
 // preflightSpreadsheet checks CSV file exists and environment variables in path.
-func preflightSpreadsheet(def *neta.Definition) error {
+func preflightSpreadsheet(def *neta.Definition, vars map[string]interface{}) error {
 	operation, _ := def.Parameters["operation"].(string)
 	if operation != "read" {
 		return nil
@@ -180,7 +180,7 @@ func preflightSpreadsheet(def *neta.Definition) error {
 	}
 
 	// Check environment variables in path first
-	if err := checkPathEnvVars(def, path); err != nil {
+	if err := checkPathEnvVars(def, vars, path); err != nil {
 		return err
 	}
 
@@ -195,17 +195,26 @@ func preflightSpreadsheet(def *neta.Definition) error {
 }
 
 // checkPathEnvVars validates environment variables in a file path.
-func checkPathEnvVars(def *neta.Definition, path string) error {
+func checkPathEnvVars(def *neta.Definition, vars map[string]interface{}, path string) error {
 	envVars := extractEnvVars(path)
 	for _, envVar := range envVars {
-		if os.Getenv(envVar) == "" {
-			return fmt.Errorf("neta '%s': environment variable '%s' not set (required in path: %s)",
+		if !varSet(vars, envVar) {
+			return fmt.Errorf("neta '%s': variable '%s' not set (required in path: %s)",
 				def.ID, envVar, path)
 		}
 	}
 	return nil
 }
 
+// varSet reports whether a template variable has a value: a resolved bento
+// variable (from --set, a vars file or its default) or an environment variable.
+func varSet(vars map[string]interface{}, name string) bool {
+	if value, ok := vars[name]; ok && value != nil && value != "" {
+		return true
+	}
+	return os.Getenv(name) != ""
+}
+
 // containsTemplates checks if a string contains any Go template syntax.
 // Returns true if it contains {{.VAR}}, {{.item.field}}, {{.index}}, etc.
 func containsTemplates(s string) bool {
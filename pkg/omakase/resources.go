@@ -0,0 +1,40 @@
+package omakase
+
+import (
+	"fmt"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// validateResources validates a bento's declared resources.
+// Each needs a name and a limit of at least one concurrent node.
+func validateResources(def *neta.Definition) error {
+	for name, limit := range def.Resources {
+		if name == "" {
+			return fmt.Errorf("neta '%s' declares a resource without a name", def.ID)
+		}
+		if limit < 1 {
+			return fmt.Errorf("neta '%s' declares resource '%s' with limit %d (must be at least 1)",
+				def.ID, name, limit)
+		}
+	}
+	return nil
+}
+
+// validateResourceUses checks that every node's uses names one of the
+// bento's resources. A sub-bento declares the resources it uses itself.
+func validateResourceUses(def *neta.Definition, resources map[string]int) error {
+	if _, ok := resources[def.Uses]; def.Uses != "" && !ok {
+		return fmt.Errorf("neta '%s' uses undeclared resource '%s' (declare it in the bento's resources)",
+			def.ID, def.Uses)
+	}
+
+	for _, children := range [][]neta.Definition{def.Nodes, def.Catch, def.OnCancel, def.Finally} {
+		for idx := range children {
+			if err := validateResourceUses(&children[idx], resources); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -2,10 +2,68 @@ package omakase
 
 import (
 	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/expr-lang/expr"
 
 	"github.com/Develonaut/bento/pkg/neta"
 )
 
+// validateTimeout validates the optional engine-enforced timeout of any neta.
+func validateTimeout(def *neta.Definition) error {
+	if def.Timeout == "" {
+		return nil
+	}
+
+	limit, err := time.ParseDuration(def.Timeout)
+	if err != nil || limit <= 0 {
+		return fmt.Errorf("neta '%s' timeout '%s' must be a positive duration (e.g. \"5m\")", def.ID, def.Timeout)
+	}
+	return nil
+}
+
+// validateWhen checks that the optional when condition of any neta compiles.
+func validateWhen(def *neta.Definition) error {
+	if def.When == "" {
+		return nil
+	}
+
+	if _, err := expr.Compile(def.When, expr.AllowUndefinedVariables(), expr.AsBool()); err != nil {
+		return fmt.Errorf("neta '%s' when condition is invalid: %w", def.ID, err)
+	}
+	return nil
+}
+
+// validateRetry validates the optional retry policy of any neta.
+func validateRetry(def *neta.Definition) error {
+	retry := def.Retry
+	if retry == nil {
+		return nil
+	}
+
+	if retry.MaxAttempts < 1 {
+		return fmt.Errorf("neta '%s' retry.maxAttempts must be at least 1", def.ID)
+	}
+
+	for field, value := range map[string]string{"backoff": retry.Backoff, "maxDelay": retry.MaxDelay} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("neta '%s' retry.%s '%s' is not a valid duration (e.g. \"2s\")", def.ID, field, value)
+		}
+	}
+
+	if retry.RetryOn != nil && retry.RetryOn.ErrorPattern != "" {
+		if _, err := regexp.Compile(retry.RetryOn.ErrorPattern); err != nil {
+			return fmt.Errorf("neta '%s' retry.retryOn.errorPattern is invalid: %w", def.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // validateHTTPRequest validates http-request neta parameters.
 func validateHTTPRequest(def *neta.Definition) error {
 	// Check URL parameter
@@ -37,7 +95,7 @@ func validateLoop(def *neta.Definition) error {
 	}
 
 	if !isValidLoopMode(mode) {
-		return fmt.Errorf("loop neta '%s' has invalid mode '%s' (must be forEach, times, or while)",
+		return fmt.Errorf("loop neta '%s' has invalid mode '%s' (must be forEach, files, times, or while)",
 			def.ID, mode)
 	}
 
@@ -51,6 +109,10 @@ func validateLoopModeParams(def *neta.Definition, mode string) error {
 		if def.Parameters["items"] == nil {
 			return fmt.Errorf("loop neta '%s' with mode 'forEach' missing required parameter 'items'", def.ID)
 		}
+	case "files":
+		if def.Parameters["pattern"] == nil {
+			return fmt.Errorf("loop neta '%s' with mode 'files' missing required parameter 'pattern'", def.ID)
+		}
 	case "times":
 		if def.Parameters["count"] == nil {
 			return fmt.Errorf("loop neta '%s' with mode 'times' missing required parameter 'count'", def.ID)
@@ -59,6 +121,20 @@ func validateLoopModeParams(def *neta.Definition, mode string) error {
 		if def.Parameters["condition"] == nil {
 			return fmt.Errorf("loop neta '%s' with mode 'while' missing required parameter 'condition'", def.ID)
 		}
+		return validateWhileInterval(def)
+	}
+	return nil
+}
+
+// validateWhileInterval validates the optional wait between while loop passes.
+func validateWhileInterval(def *neta.Definition) error {
+	raw, ok := def.Parameters["interval"]
+	if !ok {
+		return nil
+	}
+	s, _ := raw.(string)
+	if d, err := time.ParseDuration(s); err != nil || d <= 0 {
+		return fmt.Errorf("loop neta '%s' interval '%v' must be a positive duration (e.g. \"30s\")", def.ID, raw)
 	}
 	return nil
 }
@@ -105,6 +181,55 @@ func validateEditFields(def *neta.Definition) error {
 func validateParallel(def *neta.Definition) error {
 	// Parallel neta are similar to groups
 	// No specific required parameters
+	strategy, ok := def.Parameters["errorStrategy"]
+	if !ok {
+		return nil
+	}
+
+	switch strategy {
+	case "failFast", "collectAll", "bestEffort":
+		return nil
+	}
+	return fmt.Errorf("parallel neta '%s' has invalid errorStrategy '%v' (must be failFast, collectAll, or bestEffort)",
+		def.ID, strategy)
+}
+
+// validateIf validates if neta parameters.
+func validateIf(def *neta.Definition) error {
+	switch condition := def.Parameters["condition"].(type) {
+	case bool:
+		return nil
+	case string:
+		if condition != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("if neta '%s' missing required parameter 'condition'", def.ID)
+}
+
+// validateSwitch validates switch neta parameters.
+func validateSwitch(def *neta.Definition) error {
+	expression, ok := def.Parameters["expression"].(string)
+	if !ok || expression == "" {
+		return fmt.Errorf("switch neta '%s' missing required parameter 'expression'", def.ID)
+	}
+
+	return nil
+}
+
+// validateSubBento validates bento (sub-bento) neta parameters.
+func validateSubBento(def *neta.Definition) error {
+	name, _ := def.Parameters["bento"].(string)
+	path, _ := def.Parameters["path"].(string)
+	if (name == "") == (path == "") {
+		return fmt.Errorf("bento neta '%s' requires exactly one of 'bento' or 'path'", def.ID)
+	}
+
+	if inputs, ok := def.Parameters["inputs"]; ok {
+		if _, ok := inputs.(map[string]interface{}); !ok {
+			return fmt.Errorf("bento neta '%s' parameter 'inputs' must be an object", def.ID)
+		}
+	}
 	return nil
 }
 
@@ -144,6 +269,7 @@ func isValidHTTPMethod(method string) bool {
 func isValidLoopMode(mode string) bool {
 	validModes := map[string]bool{
 		"forEach": true,
+		"files":   true,
 		"times":   true,
 		"while":   true,
 	}
@@ -0,0 +1,190 @@
+package omakase
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Develonaut/bento/pkg/neta"
+)
+
+// validateVariables validates a bento's declared variables.
+// Defaults must already satisfy the variable's type and options.
+func validateVariables(def *neta.Definition) error {
+	seen := make(map[string]bool)
+	for _, v := range def.Variables {
+		if v.Name == "" {
+			return fmt.Errorf("neta '%s' has a variable without a name", def.ID)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("neta '%s' declares variable '%s' more than once", def.ID, v.Name)
+		}
+		seen[v.Name] = true
+
+		if err := validateVariableDeclaration(v); err != nil {
+			return fmt.Errorf("neta '%s': %w", def.ID, err)
+		}
+	}
+	return nil
+}
+
+// validateVariableDeclaration checks the type, options and default of one variable.
+func validateVariableDeclaration(v neta.Variable) error {
+	switch variableType(v) {
+	case neta.VariableString, neta.VariableNumber, neta.VariableInteger,
+		neta.VariableBoolean, neta.VariablePath:
+	case neta.VariableSelect:
+		if len(v.Options) == 0 {
+			return fmt.Errorf("select variable '%s' requires options", v.Name)
+		}
+	default:
+		return fmt.Errorf("variable '%s' has unknown type '%s' (use string, number, integer, boolean, path or select)",
+			v.Name, v.Type)
+	}
+
+	if def := v.DefaultOrLegacy(); def != nil {
+		if _, err := coerceVariable(v, def); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolveVariables type-checks values for a bento's declared variables.
+//
+// Each declared variable takes the first value found in: values, the
+// environment, its default. Values may be strings (from --set or a form)
+// or JSON values (from a vars file); they are converted to the declared type.
+// Values for undeclared names are passed through unchanged.
+//
+// Returns an error naming every variable that is missing or invalid, so the
+// run can fail before it starts.
+func ResolveVariables(vars []neta.Variable, values map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(values)+len(vars))
+	for name, value := range values {
+		resolved[name] = value
+	}
+
+	var problems []string
+	for _, v := range vars {
+		raw, ok := variableValue(v, values)
+		if !ok {
+			if v.Required {
+				problems = append(problems, fmt.Sprintf("variable '%s' is required", v.Name))
+			}
+			continue
+		}
+
+		value, err := coerceVariable(v, raw)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		resolved[v.Name] = value
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid variables: %s", strings.Join(problems, "; "))
+	}
+	return resolved, nil
+}
+
+// variableValue returns the raw value for a variable (given, environment or default).
+func variableValue(v neta.Variable, values map[string]interface{}) (interface{}, bool) {
+	if value, ok := values[v.Name]; ok {
+		return value, true
+	}
+	if value, ok := os.LookupEnv(v.Name); ok {
+		return value, true
+	}
+	if def := v.DefaultOrLegacy(); def != nil {
+		return def, true
+	}
+	return nil, false
+}
+
+// variableType returns the declared type, defaulting to string.
+func variableType(v neta.Variable) string {
+	if v.Type == "" {
+		return neta.VariableString
+	}
+	return v.Type
+}
+
+// coerceVariable converts a raw value to the variable's declared type.
+func coerceVariable(v neta.Variable, raw interface{}) (interface{}, error) {
+	switch variableType(v) {
+	case neta.VariableNumber:
+		return coerceNumber(v, raw)
+	case neta.VariableInteger:
+		return coerceInteger(v, raw)
+	case neta.VariableBoolean:
+		return coerceBoolean(v, raw)
+	case neta.VariableSelect:
+		return coerceSelect(v, raw)
+	default:
+		return fmt.Sprint(raw), nil
+	}
+}
+
+// coerceNumber converts a raw value to float64.
+func coerceNumber(v neta.Variable, raw interface{}) (interface{}, error) {
+	switch value := raw.(type) {
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("variable '%s' must be a number, got %q", v.Name, fmt.Sprint(raw))
+}
+
+// coerceInteger converts a raw value to int.
+func coerceInteger(v neta.Variable, raw interface{}) (interface{}, error) {
+	switch value := raw.(type) {
+	case int:
+		return value, nil
+	case float64:
+		if value == math.Trunc(value) {
+			return int(value), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("variable '%s' must be an integer, got %q", v.Name, fmt.Sprint(raw))
+}
+
+// coerceBoolean converts a raw value to bool.
+func coerceBoolean(v neta.Variable, raw interface{}) (interface{}, error) {
+	switch value := raw.(type) {
+	case bool:
+		return value, nil
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("variable '%s' must be true or false, got %q", v.Name, fmt.Sprint(raw))
+}
+
+// coerceSelect checks that a raw value is one of the variable's option values.
+func coerceSelect(v neta.Variable, raw interface{}) (interface{}, error) {
+	value := fmt.Sprint(raw)
+	values := make([]string, len(v.Options))
+	for i, opt := range v.Options {
+		values[i] = strings.SplitN(opt, "|", 2)[0]
+		if values[i] == value {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("variable '%s' must be one of [%s], got %q", v.Name, strings.Join(values, ", "), value)
+}